@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier/checks"
+	apireport "github.com/redhat-certification/chart-verifier/pkg/chartverifier/report"
+	"github.com/redhat-certification/chart-verifier/pkg/chartverifier/reportsummary"
+)
+
+const (
+	// exitPassed is the exit code when all mandatory checks passed.
+	exitPassed = 0
+	// exitToolError is the exit code when the tool or its configuration failed.
+	exitToolError = 1
+	// exitMandatoryFailed is the exit code when a mandatory check failed.
+	exitMandatoryFailed = 2
+	// exitOptionalFailed is the exit code when only checks which are not mandatory failed.
+	exitOptionalFailed = 3
+	// exitClusterUnavailable is the exit code when chart testing could not reach its cluster.
+	exitClusterUnavailable = 4
+
+	failOnOptional  = "optional"
+	failOnMandatory = "mandatory"
+	failOnNone      = "none"
+)
+
+// exitError is an error the command exits with a specific code for.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+// getExitCode returns the code the process exits with for the error returned by a command.
+func getExitCode(err error) int {
+	if err == nil {
+		return exitPassed
+	}
+	if exitErr, ok := err.(*exitError); ok {
+		return exitErr.code
+	}
+	return exitToolError
+}
+
+// checkFailOn returns an error if the --fail-on policy is not supported.
+func checkFailOn(failOn string) error {
+	switch failOn {
+	case failOnOptional, failOnMandatory, failOnNone:
+		return nil
+	}
+	return fmt.Errorf("--fail-on value is invalid: %s, must be one of %s, %s or %s", failOn, failOnOptional, failOnMandatory, failOnNone)
+}
+
+// evaluateReport returns an error with the exit code of the outcome of a report under the --fail-on policy, or nil if
+// the policy accepts the outcome. The checks failed and their type come from the profile of the report, as in the
+// results of the report summary. A check which could not reach its cluster gives its exit code whatever the policy,
+// as the outcome of the chart is then unknown.
+func evaluateReport(report *apireport.Report, failOn string) error {
+
+	clusterUnavailableCheck := ""
+	for _, result := range report.Results {
+		if result.Outcome == apireport.FailOutcomeType && result.ClusterUnavailable {
+			clusterUnavailableCheck = string(result.Check)
+		}
+	}
+
+	outcome := reportsummary.GetResultsOutcome(report, nil)
+
+	mandatoryFailed := 0
+	for _, check := range outcome.MandatoryFailed {
+		if string(check) != clusterUnavailableCheck {
+			mandatoryFailed++
+		}
+	}
+
+	if failOn != failOnNone && mandatoryFailed > 0 {
+		return &exitError{exitMandatoryFailed, fmt.Errorf("%d mandatory checks failed", mandatoryFailed)}
+	}
+	if len(clusterUnavailableCheck) > 0 {
+		return &exitError{exitClusterUnavailable, fmt.Errorf("%s could not be run: %s", clusterUnavailableCheck, checks.ClusterUnavailable)}
+	}
+	if failOn == failOnOptional && len(outcome.OptionalFailed) > 0 {
+		return &exitError{exitOptionalFailed, fmt.Errorf("%d optional checks failed", len(outcome.OptionalFailed))}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier/checks"
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier/utils"
+	apiChecks "github.com/redhat-certification/chart-verifier/pkg/chartverifier/checks"
+	apiReport "github.com/redhat-certification/chart-verifier/pkg/chartverifier/report"
+)
+
+// loadTestReport loads a partner report in which every mandatory check passed.
+func loadTestReport(t *testing.T) *apiReport.Report {
+	content, err := ioutil.ReadFile("../pkg/chartverifier/reportsummary/test-reports/report.yaml")
+	require.NoError(t, err)
+	report, err := apiReport.NewReport().SetContent(string(content)).Load()
+	require.NoError(t, err)
+	return report
+}
+
+func setOutcome(report *apiReport.Report, check apiChecks.CheckName, outcome apiReport.OutcomeType, reason string) {
+	for _, result := range report.Results {
+		if result.Check == check {
+			result.Outcome = outcome
+			result.Reason = reason
+			return
+		}
+	}
+	report.Results = append(report.Results, &apiReport.CheckReport{Check: check, Outcome: outcome, Reason: reason})
+}
+
+func setClusterUnavailable(report *apiReport.Report, check apiChecks.CheckName) {
+	for _, result := range report.Results {
+		if result.Check == check {
+			result.ClusterUnavailable = true
+		}
+	}
+}
+
+func TestEvaluateReport(t *testing.T) {
+
+	t.Run("Should pass when all mandatory checks passed", func(t *testing.T) {
+		report := loadTestReport(t)
+		setOutcome(report, "v1.0/has-complete-readme", apiReport.PassOutcomeType, "")
+		require.NoError(t, evaluateReport(report, failOnOptional))
+		require.NoError(t, evaluateReport(report, failOnMandatory))
+	})
+
+	t.Run("Should fail on a mandatory failure unless the policy is none", func(t *testing.T) {
+		report := loadTestReport(t)
+		setOutcome(report, "v1.0/helm-lint", apiReport.FailOutcomeType, "Helm lint has failed")
+		require.Equal(t, exitMandatoryFailed, getExitCode(evaluateReport(report, failOnOptional)))
+		require.Equal(t, exitMandatoryFailed, getExitCode(evaluateReport(report, failOnMandatory)))
+		require.NoError(t, evaluateReport(report, failOnNone))
+	})
+
+	t.Run("Should fail on a missing mandatory check", func(t *testing.T) {
+		report := loadTestReport(t)
+		report.Results = report.Results[1:]
+		require.Equal(t, exitMandatoryFailed, getExitCode(evaluateReport(report, failOnMandatory)))
+	})
+
+	t.Run("Should fail on an optional failure only if the policy is optional", func(t *testing.T) {
+		report := loadTestReport(t)
+		// has-complete-readme is an optional check of the v1.3 profiles.
+		report.Metadata.ToolMetadata.Profile.Version = "v1.3"
+		setOutcome(report, "v1.0/has-complete-readme", apiReport.FailOutcomeType, "Missing README sections")
+		require.Equal(t, exitOptionalFailed, getExitCode(evaluateReport(report, failOnOptional)))
+		require.NoError(t, evaluateReport(report, failOnMandatory))
+	})
+
+	t.Run("Should report an unavailable cluster", func(t *testing.T) {
+		report := loadTestReport(t)
+		setOutcome(report, "v1.0/chart-testing", apiReport.FailOutcomeType, checks.ClusterUnavailable+" : connection refused")
+		setClusterUnavailable(report, "v1.0/chart-testing")
+		// has-complete-readme is an optional check of the v1.3 profiles.
+		report.Metadata.ToolMetadata.Profile.Version = "v1.3"
+		setOutcome(report, "v1.0/has-complete-readme", apiReport.FailOutcomeType, "Missing README sections")
+		require.Equal(t, exitClusterUnavailable, getExitCode(evaluateReport(report, failOnOptional)))
+		require.Equal(t, exitClusterUnavailable, getExitCode(evaluateReport(report, failOnMandatory)))
+		require.Equal(t, exitClusterUnavailable, getExitCode(evaluateReport(report, failOnNone)))
+
+		setOutcome(report, "v1.0/helm-lint", apiReport.FailOutcomeType, "Helm lint has failed")
+		err := evaluateReport(report, failOnMandatory)
+		require.Equal(t, exitMandatoryFailed, getExitCode(err))
+		require.EqualError(t, err, "1 mandatory checks failed")
+	})
+
+	t.Run("Should fail on a chart testing failure whose logs mention an unavailable cluster", func(t *testing.T) {
+		report := loadTestReport(t)
+		setOutcome(report, "v1.0/chart-testing", apiReport.FailOutcomeType, "Chart test failure : hook logs : "+checks.ClusterUnavailable)
+		require.Equal(t, exitMandatoryFailed, getExitCode(evaluateReport(report, failOnMandatory)))
+	})
+}
+
+func TestGetExitCode(t *testing.T) {
+	require.Equal(t, exitPassed, getExitCode(nil))
+	require.Equal(t, exitToolError, getExitCode(errors.New("error")))
+	require.Equal(t, exitOptionalFailed, getExitCode(&exitError{exitOptionalFailed, errors.New("error")}))
+}
+
+func TestFailOn(t *testing.T) {
+
+	t.Run("Should fail with a tool error on an invalid policy", func(t *testing.T) {
+		cmd := NewVerifyCmd(viper.New())
+		cmd.SetOut(bytes.NewBufferString(""))
+		cmd.SetErr(bytes.NewBufferString(""))
+		cmd.SetArgs([]string{"-E", "--fail-on", "all", "../internal/chartverifier/checks/chart-0.1.0-v3.valid.tgz"})
+
+		err := cmd.Execute()
+		require.EqualError(t, err, "--fail-on value is invalid: all, must be one of optional, mandatory or none")
+		require.Equal(t, exitToolError, getExitCode(err))
+	})
+
+	t.Run("Should fail with the mandatory failure exit code", func(t *testing.T) {
+		cmd := NewVerifyCmd(viper.New())
+		cmd.SetErr(bytes.NewBufferString(""))
+		utils.CmdStdout = bytes.NewBufferString("")
+		cmd.SetArgs([]string{"-e", "is-helm-v3", "-E", "--fail-on", "mandatory", "../internal/chartverifier/checks/chart-0.1.0-v2.invalid.tgz"})
+
+		require.Equal(t, exitMandatoryFailed, getExitCode(cmd.Execute()))
+	})
+
+	t.Run("Should fail on a mandatory failure by default", func(t *testing.T) {
+		cmd := NewVerifyCmd(viper.New())
+		cmd.SetErr(bytes.NewBufferString(""))
+		utils.CmdStdout = bytes.NewBufferString("")
+		cmd.SetArgs([]string{"-e", "is-helm-v3", "-E", "../internal/chartverifier/checks/chart-0.1.0-v2.invalid.tgz"})
+
+		require.Equal(t, exitMandatoryFailed, getExitCode(cmd.Execute()))
+	})
+
+	t.Run("Should succeed on a mandatory failure if the policy is none", func(t *testing.T) {
+		cmd := NewVerifyCmd(viper.New())
+		cmd.SetErr(bytes.NewBufferString(""))
+		utils.CmdStdout = bytes.NewBufferString("")
+		cmd.SetArgs([]string{"-e", "is-helm-v3", "-E", "--fail-on", "none", "../internal/chartverifier/checks/chart-0.1.0-v2.invalid.tgz"})
+
+		require.NoError(t, cmd.Execute())
+	})
+}
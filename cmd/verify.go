@@ -23,6 +23,7 @@ import (
 
 	"github.com/redhat-certification/chart-verifier/internal/chartverifier/utils"
 	"github.com/redhat-certification/chart-verifier/internal/tool"
+	"github.com/redhat-certification/chart-verifier/pkg/chartverifier/changes"
 	apiChecks "github.com/redhat-certification/chart-verifier/pkg/chartverifier/checks"
 	apireport "github.com/redhat-certification/chart-verifier/pkg/chartverifier/report"
 	apiverifier "github.com/redhat-certification/chart-verifier/pkg/chartverifier/verifier"
@@ -68,6 +69,18 @@ var (
 	pgpPublicKeyFile string
 	//helm install timeout
 	helmInstallTimeout time.Duration
+	// keep the release of a failed chart test
+	keepOnFailure bool
+	// run chart testing on a throwaway local cluster
+	ephemeralCluster bool
+	// file or url of the Kubernetes to OpenShift version map
+	versionMapFlag string
+	// git ref the charts of a working tree are verified if they changed since
+	changedSinceFlag string
+	// the check failures the verify command exits with an error for: optional, mandatory or none
+	failOnFlag string
+	// file or url of the baseline of accepted check failures
+	baselineFlag string
 )
 
 func buildChecks(enabled []string, unEnabled []string) ([]apiChecks.CheckName, []apiChecks.CheckName, error) {
@@ -127,6 +140,94 @@ type verifyOptions struct {
 	Values     []string
 }
 
+// newVerifier returns a verifier configured from the command line, ready to run on a chart.
+func newVerifier(opts *values.Options, verifyOpts *verifyOptions) (apiverifier.ApiVerifier, error) {
+
+	enabledChecks, unEnabledChecks, checksErr := buildChecks(enabledChecksFlag, disabledChecksFlag)
+	if checksErr != nil {
+		return nil, checksErr
+	}
+
+	valueMap := convertToMap(verifyOpts.Values)
+	for key, val := range viper.AllSettings() {
+		valueMap[strings.ToLower(key)] = val
+	}
+
+	verifier := apiverifier.NewVerifier()
+
+	if len(enabledChecks) > 0 {
+		verifier = verifier.EnableChecks(enabledChecks)
+	} else if len(unEnabledChecks) > 0 {
+		verifier = verifier.UnEnableChecks(unEnabledChecks)
+	}
+
+	encodedKey, err := tool.GetEncodedKey(pgpPublicKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return verifier.SetBoolean(apiverifier.WebCatalogOnly, webCatalogOnly).
+		SetBoolean(apiverifier.SuppressErrorLog, suppressErrorLog).
+		SetBoolean(apiverifier.KeepOnFailure, keepOnFailure).
+		SetBoolean(apiverifier.EphemeralCluster, ephemeralCluster).
+		SetDuration(apiverifier.Timeout, clientTimeout).
+		SetDuration(apiverifier.HelmInstallTimeout, helmInstallTimeout).
+		SetString(apiverifier.OpenshiftVersion, []string{openshiftVersionFlag}).
+		SetString(apiverifier.VersionMap, []string{versionMapFlag}).
+		SetString(apiverifier.Baseline, []string{baselineFlag}).
+		SetString(apiverifier.ChartValues, opts.ValueFiles).
+		SetString(apiverifier.KubeApiServer, []string{settings.KubeAPIServer}).
+		SetString(apiverifier.KubeAsUser, []string{settings.KubeAsUser}).
+		SetString(apiverifier.KubeCaFile, []string{settings.KubeCaFile}).
+		SetString(apiverifier.KubeConfig, []string{settings.KubeConfig}).
+		SetString(apiverifier.KubeContext, []string{settings.KubeContext}).
+		SetString(apiverifier.Namespace, []string{settings.Namespace()}).
+		SetString(apiverifier.KubeApiServer, []string{settings.KubeAPIServer}).
+		SetString(apiverifier.RegistryConfig, []string{settings.RegistryConfig}).
+		SetString(apiverifier.RepositoryConfig, []string{settings.RepositoryConfig}).
+		SetString(apiverifier.RepositoryCache, []string{settings.RepositoryCache}).
+		SetString(apiverifier.KubeAsGroups, settings.KubeAsGroups).
+		SetValues(apiverifier.CommandSet, valueMap).
+		SetValues(apiverifier.ChartSet, convertToMap(opts.Values)).
+		SetValues(apiverifier.ChartSetFile, convertToMap(opts.FileValues)).
+		SetValues(apiverifier.ChartSetString, convertToMap(opts.StringValues)).
+		SetString(apiverifier.PGPPublicKey, []string{encodedKey}), nil
+}
+
+// addVerifyFlags adds the flags configuring how charts are verified, shared by the verify and verify-all commands.
+func addVerifyFlags(cmd *cobra.Command, opts *values.Options, verifyOpts *verifyOptions) {
+
+	settings.AddFlags(cmd.Flags())
+
+	cmd.Flags().StringSliceVarP(&opts.ValueFiles, "chart-values", "F", nil, "specify values in a YAML file or a URL (can specify multiple)")
+
+	cmd.Flags().StringSliceVarP(&opts.Values, "chart-set", "S", nil, "set values for the chart (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+
+	cmd.Flags().StringSliceVarP(&opts.StringValues, "chart-set-string", "X", nil, "set STRING values for the chart (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+
+	cmd.Flags().StringSliceVarP(&opts.FileValues, "chart-set-file", "G", nil, "set values from respective files specified via the command line (can specify multiple or separate values with commas: key1=path1,key2=path2)")
+
+	cmd.Flags().StringSliceVarP(&enabledChecksFlag, "enable", "e", nil, "only the informed checks will be enabled")
+
+	cmd.Flags().StringSliceVarP(&disabledChecksFlag, "disable", "x", nil, "all checks will be enabled except the informed ones")
+
+	cmd.Flags().StringVarP(&outputFormatFlag, "output", "o", "", "the output format: default, json or yaml")
+
+	cmd.Flags().StringSliceVarP(&verifyOpts.Values, "set", "s", []string{}, "overrides a configuration, e.g: dummy.ok=false")
+
+	cmd.Flags().StringSliceVarP(&verifyOpts.ValueFiles, "set-values", "f", nil, "specify application and check configuration values in a YAML file or a URL (can specify multiple)")
+	cmd.Flags().StringVarP(&openshiftVersionFlag, "openshift-version", "V", "", "version of OpenShift used in the cluster")
+	cmd.Flags().DurationVar(&clientTimeout, "timeout", 30*time.Minute, "time to wait for completion of chart install and test")
+	cmd.Flags().BoolVarP(&suppressErrorLog, "suppress-error-log", "E", false, "suppress the error log (default: written to ./chartverifier/verifier-<timestamp>.log)")
+	cmd.Flags().BoolVarP(&webCatalogOnly, "web-catalog-only", "W", false, "set this to indicate that the distribution method is web catalog only (default: false)")
+	cmd.Flags().StringVarP(&pgpPublicKeyFile, "pgp-public-key", "k", "", "file containing gpg public key of the key used to sign the chart")
+	cmd.Flags().DurationVar(&helmInstallTimeout, "helm-install-timeout", 5*time.Minute, "helm install timeout")
+	cmd.Flags().BoolVar(&keepOnFailure, "keep-on-failure", false, "keep the release and namespace of a failed chart test for debugging (default: false)")
+	cmd.Flags().StringVar(&versionMapFlag, "version-map", "", "file or url of the Kubernetes to OpenShift version map to use instead of the map embedded in chart-verifier")
+	cmd.Flags().BoolVar(&ephemeralCluster, "ephemeral-cluster", false, "run chart testing on a throwaway local kind cluster instead of the cluster of the kubeconfig (default: false)")
+	cmd.Flags().StringVar(&baselineFlag, "baseline", "", "file or url of a baseline listing check failures to report as accepted rather than failed")
+}
+
 // NewVerifyCmd creates ...
 func NewVerifyCmd(config *viper.Viper) *cobra.Command {
 
@@ -136,12 +237,28 @@ func NewVerifyCmd(config *viper.Viper) *cobra.Command {
 	// verifyOpts contains this specific command options.
 	verifyOpts := &verifyOptions{}
 
+	// verifyAllOpts contains the options of the charts verified with --changed-since.
+	verifyAllOpts := &verifyAllOptions{}
+
 	cmd := &cobra.Command{
-		Use:   "verify <chart-uri>",
+		Use:   "verify <chart-uri> | --changed-since <git-ref> <repo-dir>",
 		Args:  cobra.ExactArgs(1),
 		Short: "Verifies a Helm chart by checking some of its characteristics",
 		RunE: func(cmd *cobra.Command, args []string) error {
 
+			if err := checkFailOn(failOnFlag); err != nil {
+				return err
+			}
+
+			if err := checkChangedSinceFlags(cmd); err != nil {
+				return err
+			}
+
+			if len(changedSinceFlag) > 0 {
+				verifyAllOpts.failOn = failOnFlag
+				return verifyChangedCharts(cmd, args[0], opts, verifyOpts, verifyAllOpts)
+			}
+
 			reportFormat := apireport.YamlReport
 			if outputFormatFlag == "json" {
 				reportFormat = apireport.JsonReport
@@ -156,8 +273,7 @@ func NewVerifyCmd(config *viper.Viper) *cobra.Command {
 				}
 			}
 
-			enabledChecks, unEnabledChecks, checksErr := buildChecks(enabledChecksFlag, disabledChecksFlag)
-			if checksErr != nil {
+			if _, _, checksErr := buildChecks(enabledChecksFlag, disabledChecksFlag); checksErr != nil {
 				return checksErr
 			}
 
@@ -168,48 +284,13 @@ func NewVerifyCmd(config *viper.Viper) *cobra.Command {
 			utils.LogInfo(fmt.Sprintf("Client timeout: %s", clientTimeout))
 			utils.LogInfo(fmt.Sprintf("Helm Install timeout: %s", helmInstallTimeout))
 
-			valueMap := convertToMap(verifyOpts.Values)
-			for key, val := range viper.AllSettings() {
-				valueMap[strings.ToLower(key)] = val
-			}
-
-			verifier := apiverifier.NewVerifier()
-
-			if len(enabledChecks) > 0 {
-				verifier = verifier.EnableChecks(enabledChecks)
-			} else if len(unEnabledChecks) > 0 {
-				verifier = verifier.UnEnableChecks(unEnabledChecks)
-			}
-
-			encodedKey, err := tool.GetEncodedKey(pgpPublicKeyFile)
+			verifier, err := newVerifier(opts, verifyOpts)
 			if err != nil {
 				return err
 			}
 
 			var runErr error
-			verifier, runErr = verifier.SetBoolean(apiverifier.WebCatalogOnly, webCatalogOnly).
-				SetBoolean(apiverifier.SuppressErrorLog, suppressErrorLog).
-				SetDuration(apiverifier.Timeout, clientTimeout).
-				SetDuration(apiverifier.HelmInstallTimeout, helmInstallTimeout).
-				SetString(apiverifier.OpenshiftVersion, []string{openshiftVersionFlag}).
-				SetString(apiverifier.ChartValues, opts.ValueFiles).
-				SetString(apiverifier.KubeApiServer, []string{settings.KubeAPIServer}).
-				SetString(apiverifier.KubeAsUser, []string{settings.KubeAsUser}).
-				SetString(apiverifier.KubeCaFile, []string{settings.KubeCaFile}).
-				SetString(apiverifier.KubeConfig, []string{settings.KubeConfig}).
-				SetString(apiverifier.KubeContext, []string{settings.KubeContext}).
-				SetString(apiverifier.Namespace, []string{settings.Namespace()}).
-				SetString(apiverifier.KubeApiServer, []string{settings.KubeAPIServer}).
-				SetString(apiverifier.RegistryConfig, []string{settings.RegistryConfig}).
-				SetString(apiverifier.RepositoryConfig, []string{settings.RepositoryConfig}).
-				SetString(apiverifier.RepositoryCache, []string{settings.RepositoryCache}).
-				SetString(apiverifier.KubeAsGroups, settings.KubeAsGroups).
-				SetValues(apiverifier.CommandSet, valueMap).
-				SetValues(apiverifier.ChartSet, convertToMap(opts.Values)).
-				SetValues(apiverifier.ChartSetFile, convertToMap(opts.FileValues)).
-				SetValues(apiverifier.ChartSetString, convertToMap(opts.StringValues)).
-				SetString(apiverifier.PGPPublicKey, []string{encodedKey}).
-				Run(args[0])
+			verifier, runErr = verifier.Run(args[0])
 
 			if runErr != nil {
 				return runErr
@@ -224,37 +305,59 @@ func NewVerifyCmd(config *viper.Viper) *cobra.Command {
 
 			utils.WriteLogs(outputFormatFlag)
 
+			if err = evaluateReport(verifier.GetReport(), failOnFlag); err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
 			return nil
 		},
 	}
 
-	settings.AddFlags(cmd.Flags())
-
-	cmd.Flags().StringSliceVarP(&opts.ValueFiles, "chart-values", "F", nil, "specify values in a YAML file or a URL (can specify multiple)")
-
-	cmd.Flags().StringSliceVarP(&opts.Values, "chart-set", "S", nil, "set values for the chart (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	addVerifyFlags(cmd, opts, verifyOpts)
+	cmd.Flags().BoolVarP(&reportToFile, "write-to-file", "w", false, "write report to ./chartverifier/report.yaml (default: stdout)")
+	addChangedSinceFlag(cmd)
+	addVerifyAllFlags(cmd, verifyAllOpts)
+	cmd.Flags().StringVar(&failOnFlag, "fail-on", failOnMandatory, "the check failures to exit with an error for: optional, mandatory or none")
+	return cmd
+}
 
-	cmd.Flags().StringSliceVarP(&opts.StringValues, "chart-set-string", "X", nil, "set STRING values for the chart (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+// checkChangedSinceFlags returns an error if a flag is set which cannot be used with --changed-since, or which can only
+// be used with it.
+func checkChangedSinceFlags(cmd *cobra.Command) error {
+	if len(changedSinceFlag) > 0 {
+		if cmd.Flags().Changed("write-to-file") {
+			return errors.New("--write-to-file cannot be used with --changed-since, the reports are written to --report-dir")
+		}
+		return nil
+	}
+	for _, flag := range []string{"concurrency", "report-dir"} {
+		if cmd.Flags().Changed(flag) {
+			return fmt.Errorf("--%s can only be used with --changed-since", flag)
+		}
+	}
+	return nil
+}
 
-	cmd.Flags().StringSliceVarP(&opts.FileValues, "chart-set-file", "G", nil, "set values from respective files specified via the command line (can specify multiple or separate values with commas: key1=path1,key2=path2)")
+// verifyChangedCharts verifies the charts of a git working tree changed since the --changed-since ref as verify-all
+// does, writing the report of each chart to <report-dir>/<name>/<version> and a summary to stdout, and evaluating each
+// report under the --fail-on policy.
+func verifyChangedCharts(cmd *cobra.Command, repoDir string, opts *values.Options, verifyOpts *verifyOptions, verifyAllOpts *verifyAllOptions) error {
 
-	cmd.Flags().StringSliceVarP(&enabledChecksFlag, "enable", "e", nil, "only the informed checks will be enabled")
+	if _, _, checksErr := buildChecks(enabledChecksFlag, disabledChecksFlag); checksErr != nil {
+		return checksErr
+	}
 
-	cmd.Flags().StringSliceVarP(&disabledChecksFlag, "disable", "x", nil, "all checks will be enabled except the informed ones")
+	utils.InitLog(cmd, "", suppressErrorLog)
 
-	cmd.Flags().StringVarP(&outputFormatFlag, "output", "o", "", "the output format: default, json or yaml")
+	utils.LogInfo(fmt.Sprintf("Chart Verifer %s.", apiversion.GetVersion()))
+	utils.LogInfo(fmt.Sprintf("Verify charts of %s changed since %s", repoDir, changedSinceFlag))
 
-	cmd.Flags().StringSliceVarP(&verifyOpts.Values, "set", "s", []string{}, "overrides a configuration, e.g: dummy.ok=false")
+	charts, err := changes.GetChangedCharts(repoDir, changedSinceFlag)
+	if err != nil {
+		return err
+	}
 
-	cmd.Flags().StringSliceVarP(&verifyOpts.ValueFiles, "set-values", "f", nil, "specify application and check configuration values in a YAML file or a URL (can specify multiple)")
-	cmd.Flags().StringVarP(&openshiftVersionFlag, "openshift-version", "V", "", "version of OpenShift used in the cluster")
-	cmd.Flags().DurationVar(&clientTimeout, "timeout", 30*time.Minute, "time to wait for completion of chart install and test")
-	cmd.Flags().BoolVarP(&reportToFile, "write-to-file", "w", false, "write report to ./chartverifier/report.yaml (default: stdout)")
-	cmd.Flags().BoolVarP(&suppressErrorLog, "suppress-error-log", "E", false, "suppress the error log (default: written to ./chartverifier/verifier-<timestamp>.log)")
-	cmd.Flags().BoolVarP(&webCatalogOnly, "web-catalog-only", "W", false, "set this to indicate that the distribution method is web catalog only (default: false)")
-	cmd.Flags().StringVarP(&pgpPublicKeyFile, "pgp-public-key", "k", "", "file containing gpg public key of the key used to sign the chart")
-	cmd.Flags().DurationVar(&helmInstallTimeout, "helm-install-timeout", 5*time.Minute, "helm install timeout")
-	return cmd
+	return runVerifyAll(cmd, charts, opts, verifyOpts, verifyAllOpts)
 }
 
 func init() {
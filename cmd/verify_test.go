@@ -116,6 +116,7 @@ func TestCertify(t *testing.T) {
 			"-e", "is-helm-v3",
 			"-V", "4.9",
 			"-E",
+			"--fail-on", "none",
 			"../internal/chartverifier/checks/chart-0.1.0-v3.valid.tgz",
 		})
 
@@ -142,6 +143,7 @@ func TestCertify(t *testing.T) {
 			"-V", "4.9",
 			"-o", "json",
 			"-E",
+			"--fail-on", "none",
 			"../internal/chartverifier/checks/chart-0.1.0-v3.valid.tgz",
 		})
 
@@ -173,6 +175,7 @@ func TestCertify(t *testing.T) {
 			"-o", "yaml",
 			"../internal/chartverifier/checks/chart-0.1.0-v3.valid.tgz",
 			"-E",
+			"--fail-on", "none",
 		})
 		require.NoError(t, cmd.Execute())
 		require.NotEmpty(t, outBuf.String())
@@ -201,7 +204,7 @@ func TestCertify(t *testing.T) {
 			"-e", "has-readme", // only consider a single check, perhaps more checks in the future
 			"../internal/chartverifier/checks/chart-0.1.0-v3.valid.tgz",
 			"-W",
-			"-E"})
+			"-E", "--fail-on", "none"})
 
 		require.NoError(t, cmd.Execute())
 		require.NotEmpty(t, outBuf.String())
@@ -226,7 +229,7 @@ func TestCertify(t *testing.T) {
 		cmd.SetArgs([]string{
 			"-e", "has-readme", // only consider a single check, perhaps more checks in the future
 			"../internal/chartverifier/checks/chart-0.1.0-v3.valid.tgz",
-			"-E"})
+			"-E", "--fail-on", "none"})
 
 		require.NoError(t, cmd.Execute())
 		require.NotEmpty(t, outBuf.String())
@@ -314,7 +317,7 @@ func TestSignatureCheck(t *testing.T) {
 		cmd.SetArgs([]string{
 			"-e", "signature-is-valid", // only consider a single check, perhaps more checks in the future
 			"../internal/chartverifier/checks/chart-0.1.0-v3.valid.tgz",
-			"-E"})
+			"-E", "--fail-on", "none"})
 
 		require.NoError(t, cmd.Execute())
 		require.NotEmpty(t, outBuf.String())
@@ -336,7 +339,7 @@ func TestSignatureCheck(t *testing.T) {
 			"-e", "signature-is-valid", // only consider a single check, perhaps more checks in the future
 			"-k", "../tests/charts/psql-service/0.1.11/psql-service-0.1.11.tgz.key",
 			"../internal/chartverifier/checks/chart-0.1.0-v3.valid.tgz",
-			"-E"})
+			"-E", "--fail-on", "none"})
 
 		require.NoError(t, cmd.Execute())
 		require.NotEmpty(t, outBuf.String())
@@ -358,7 +361,7 @@ func TestSignatureCheck(t *testing.T) {
 			"-e", "signature-is-valid", // only consider a single check, perhaps more checks in the future
 			"-k", "../tests/charts/psql-service/0.1.11/psql-service-0.1.11.tgz.key",
 			"../tests/charts/psql-service/0.1.11/psql-service-0.1.11.tgz",
-			"-E"})
+			"-E", "--fail-on", "none"})
 
 		require.NoError(t, cmd.Execute())
 		require.NotEmpty(t, outBuf.String())
@@ -380,7 +383,7 @@ func TestSignatureCheck(t *testing.T) {
 			"-e", "signature-is-valid", // only consider a single check, perhaps more checks in the future
 			"-k", "../tests/charts/psql-service/0.1.11/psql-service-0.1.11.tgz.badkey",
 			"../tests/charts/psql-service/0.1.11/psql-service-0.1.11.tgz",
-			"-E"})
+			"-E", "--fail-on", "none"})
 
 		require.NoError(t, cmd.Execute())
 		require.NotEmpty(t, outBuf.String())
@@ -401,7 +404,7 @@ func TestSignatureCheck(t *testing.T) {
 		cmd.SetArgs([]string{
 			"-e", "signature-is-valid",
 			"https://github.com/redhat-certification/chart-verifier/blob/main/tests/charts/psql-service/0.1.11/psql-service-0.1.11.tgz?raw=true",
-			"-E"})
+			"-E", "--fail-on", "none"})
 
 		require.NoError(t, cmd.Execute())
 		require.NotEmpty(t, outBuf.String())
@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/cli/values"
+
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier"
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier/utils"
+	"github.com/redhat-certification/chart-verifier/pkg/chartverifier/changes"
+	apiChecks "github.com/redhat-certification/chart-verifier/pkg/chartverifier/checks"
+	apireport "github.com/redhat-certification/chart-verifier/pkg/chartverifier/report"
+	"github.com/redhat-certification/chart-verifier/pkg/chartverifier/reportsummary"
+	apiversion "github.com/redhat-certification/chart-verifier/pkg/chartverifier/version"
+)
+
+const (
+	summaryFileName    = "summary"
+	defaultConcurrency = 4
+	defaultReportDir   = "chartverifier"
+)
+
+type verifyAllOptions struct {
+	// concurrency is the maximum number of charts verified at a time
+	concurrency int
+	// reportDir is the directory the report of each chart and the summary are written to
+	reportDir string
+	// failOn is the --fail-on policy each report is evaluated under
+	failOn string
+}
+
+// exitCodeSeverities orders the exit codes of the charts, the most severe being the exit code of the command.
+var exitCodeSeverities = map[int]int{
+	exitPassed:             0,
+	exitOptionalFailed:     1,
+	exitClusterUnavailable: 2,
+	exitMandatoryFailed:    3,
+	exitToolError:          4,
+}
+
+// verifyAllSummary is the outcome of the verification of each chart.
+type verifyAllSummary struct {
+	Charts []*chartSummary `json:"charts" yaml:"charts"`
+	Passed int             `json:"passed" yaml:"passed"`
+	Failed int             `json:"failed" yaml:"failed"`
+}
+
+type chartSummary struct {
+	ChartUri string `json:"chart-uri" yaml:"chart-uri"`
+	Name     string `json:"name,omitempty" yaml:"name,omitempty"`
+	Version  string `json:"version,omitempty" yaml:"version,omitempty"`
+	Passed   bool   `json:"passed" yaml:"passed"`
+	// Report is the file the report of the chart is written to.
+	Report                string                `json:"report,omitempty" yaml:"report,omitempty"`
+	FailedMandatoryChecks []apiChecks.CheckName `json:"failed-mandatory-checks,omitempty" yaml:"failed-mandatory-checks,omitempty"`
+	// Error is set when the chart could not be verified.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+	// reportContent is the report of the chart, written once every chart is verified.
+	reportContent string
+	// exitCode is the exit code of the chart under the --fail-on policy.
+	exitCode int
+}
+
+// NewVerifyAllCmd creates the command verifying every chart of a directory tree or chart repository.
+func NewVerifyAllCmd(config *viper.Viper) *cobra.Command {
+
+	// opts contains command line options extracted from the environment.
+	opts := &values.Options{}
+
+	// verifyOpts contains the options shared with the verify command.
+	verifyOpts := &verifyOptions{}
+
+	verifyAllOpts := &verifyAllOptions{failOn: failOnMandatory}
+
+	cmd := &cobra.Command{
+		Use:   "verify-all <dir|index.yaml|repo-dir>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Verifies each Helm chart of a directory tree or chart repository",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			if _, _, checksErr := buildChecks(enabledChecksFlag, disabledChecksFlag); checksErr != nil {
+				return checksErr
+			}
+
+			utils.InitLog(cmd, "", suppressErrorLog)
+
+			utils.LogInfo(fmt.Sprintf("Chart Verifer %s.", apiversion.GetVersion()))
+			utils.LogInfo(fmt.Sprintf("Verify all : %s", args[0]))
+
+			var charts []string
+			var err error
+			if len(changedSinceFlag) > 0 {
+				charts, err = changes.GetChangedCharts(args[0], changedSinceFlag)
+			} else {
+				charts, err = chartverifier.DiscoverCharts(args[0])
+				if err == nil && len(charts) == 0 {
+					err = fmt.Errorf("no charts found in %s", args[0])
+				}
+			}
+			if err != nil {
+				return err
+			}
+
+			return runVerifyAll(cmd, charts, opts, verifyOpts, verifyAllOpts)
+		},
+	}
+
+	addVerifyFlags(cmd, opts, verifyOpts)
+	addVerifyAllFlags(cmd, verifyAllOpts)
+	addChangedSinceFlag(cmd)
+	return cmd
+}
+
+// runVerifyAll verifies the charts, writes the report of each chart and the summary, and fails with the most severe
+// exit code of the charts, a chart which could not be verified being a tool error and the report of each other chart
+// being evaluated under the --fail-on policy.
+func runVerifyAll(cmd *cobra.Command, charts []string, opts *values.Options, verifyOpts *verifyOptions, verifyAllOpts *verifyAllOptions) error {
+
+	utils.LogInfo(fmt.Sprintf("Charts to verify: %d, verified %d at a time", len(charts), verifyAllOpts.concurrency))
+
+	summary := verifyCharts(charts, verifyAllOpts.concurrency, func(chartUri string) *chartSummary {
+		return verifyChart(chartUri, opts, verifyOpts, verifyAllOpts.failOn)
+	})
+	writeChartReports(summary, verifyAllOpts.reportDir)
+
+	content, err := getSummaryContent(summary, outputFormatFlag)
+	if err != nil {
+		return err
+	}
+	if err = writeReportFile(verifyAllOpts.reportDir, fmt.Sprintf("%s.%s", summaryFileName, getReportExtension()), content); err != nil {
+		utils.LogError(err.Error())
+	}
+
+	utils.WriteStdOut(content)
+
+	utils.WriteLogs(outputFormatFlag)
+
+	exitCode := exitPassed
+	failed := 0
+	for _, chart := range summary.Charts {
+		if chart.exitCode != exitPassed {
+			failed++
+		}
+		if exitCodeSeverities[chart.exitCode] > exitCodeSeverities[exitCode] {
+			exitCode = chart.exitCode
+		}
+	}
+	if exitCode != exitPassed {
+		cmd.SilenceUsage = true
+		return &exitError{exitCode, fmt.Errorf("%d of %d charts failed verification", failed, len(summary.Charts))}
+	}
+	return nil
+}
+
+// addVerifyAllFlags adds the flags of the number of charts verified at a time and of the directory their reports are
+// written to.
+func addVerifyAllFlags(cmd *cobra.Command, verifyAllOpts *verifyAllOptions) {
+	cmd.Flags().IntVar(&verifyAllOpts.concurrency, "concurrency", defaultConcurrency, "maximum number of charts verified at a time")
+	cmd.Flags().StringVar(&verifyAllOpts.reportDir, "report-dir", defaultReportDir, "directory the report of each chart, in <name>/<version>, and the summary are written to")
+}
+
+// addChangedSinceFlag adds the flag restricting the verification to the charts of a git working tree changed since a
+// git ref.
+func addChangedSinceFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&changedSinceFlag, "changed-since", "", "only verify the charts of the git working tree <repo-dir> which changed since the git ref, or which depend on a changed chart")
+}
+
+// verifyCharts verifies up to concurrency charts at a time, and returns the summary of the charts in the order given.
+func verifyCharts(charts []string, concurrency int, verify func(chartUri string) *chartSummary) *verifyAllSummary {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	summary := &verifyAllSummary{Charts: make([]*chartSummary, len(charts))}
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for index, chartUri := range charts {
+		wg.Add(1)
+		go func(index int, chartUri string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			summary.Charts[index] = verify(chartUri)
+		}(index, chartUri)
+	}
+	wg.Wait()
+
+	for _, chart := range summary.Charts {
+		if chart.Passed {
+			summary.Passed++
+		} else {
+			summary.Failed++
+		}
+	}
+	return summary
+}
+
+// verifyChart verifies a chart and gets its report, the chart failing if it cannot be verified or if a mandatory check
+// of its profile fails, and its exit code being that of its report under the --fail-on policy.
+func verifyChart(chartUri string, opts *values.Options, verifyOpts *verifyOptions, failOn string) *chartSummary {
+
+	utils.LogInfo(fmt.Sprintf("Verify : %s", chartUri))
+	chart := &chartSummary{ChartUri: chartUri, Name: path.Base(chartUri)}
+
+	verifier, err := newVerifier(opts, verifyOpts)
+	if err == nil {
+		verifier, err = verifier.Run(chartUri)
+	}
+	if err != nil {
+		utils.LogError(fmt.Sprintf("Error verifying %s : %v", chartUri, err))
+		chart.Error = err.Error()
+		chart.exitCode = exitToolError
+		return chart
+	}
+
+	report := verifier.GetReport()
+	if chartData := report.Metadata.ChartData; chartData != nil {
+		chart.Name = chartData.Name
+		chart.Version = chartData.Version
+	}
+	chart.FailedMandatoryChecks = reportsummary.GetResultsOutcome(report, nil).MandatoryFailed
+	chart.Passed = len(chart.FailedMandatoryChecks) == 0
+	chart.exitCode = getExitCode(evaluateReport(report, failOn))
+
+	reportFormat := apireport.YamlReport
+	if outputFormatFlag == "json" {
+		reportFormat = apireport.JsonReport
+	}
+	content, err := report.GetContent(reportFormat)
+	if err != nil {
+		chart.Passed = false
+		chart.Error = err.Error()
+		chart.exitCode = exitToolError
+		return chart
+	}
+	chart.reportContent = content
+	return chart
+}
+
+// writeChartReports writes the report of each chart to <name>/<version>/report.<ext>. The reports of charts of
+// different uris with the same name and version are written to <name>/<version>/report-<uri digest>.<ext> instead.
+func writeChartReports(summary *verifyAllSummary, reportDir string) {
+	chartCounts := make(map[string]int)
+	for _, chart := range summary.Charts {
+		if len(chart.reportContent) > 0 {
+			chartCounts[filepath.Join(chart.Name, chart.Version)]++
+		}
+	}
+	for _, chart := range summary.Charts {
+		if len(chart.reportContent) == 0 {
+			continue
+		}
+		chartDir := filepath.Join(chart.Name, chart.Version)
+		reportFile := filepath.Join(chartDir, fmt.Sprintf("report.%s", getReportExtension()))
+		if chartCounts[chartDir] > 1 {
+			uriDigest := sha256.Sum256([]byte(chart.ChartUri))
+			reportFile = filepath.Join(chartDir, fmt.Sprintf("report-%x.%s", uriDigest[:6], getReportExtension()))
+		}
+		if err := writeReportFile(reportDir, reportFile, chart.reportContent); err != nil {
+			utils.LogError(err.Error())
+		} else {
+			chart.Report = filepath.Join(reportDir, reportFile)
+		}
+	}
+}
+
+func getSummaryContent(summary *verifyAllSummary, format string) (string, error) {
+	if format == "json" {
+		content, err := json.Marshal(summary)
+		return string(content), err
+	}
+	content, err := yaml.Marshal(summary)
+	return string(content), err
+}
+
+func getReportExtension() string {
+	if outputFormatFlag == "json" {
+		return "json"
+	}
+	return "yaml"
+}
+
+func writeReportFile(reportDir string, fileName string, content string) error {
+	reportFile := filepath.Join(reportDir, fileName)
+	// #nosec G301
+	if err := os.MkdirAll(filepath.Dir(reportFile), 0777); err != nil {
+		return fmt.Errorf("error creating directory %s: %v", filepath.Dir(reportFile), err)
+	}
+	// #nosec G306
+	if err := ioutil.WriteFile(reportFile, []byte(content), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", reportFile, err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(NewVerifyAllCmd(viper.GetViper()))
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier/utils"
+	apiChecks "github.com/redhat-certification/chart-verifier/pkg/chartverifier/checks"
+	apiReport "github.com/redhat-certification/chart-verifier/pkg/chartverifier/report"
+)
+
+func copyTestChart(t *testing.T, source string, dir string) string {
+	content, err := ioutil.ReadFile(source)
+	require.NoError(t, err)
+	destination := filepath.Join(dir, filepath.Base(source))
+	require.NoError(t, ioutil.WriteFile(destination, content, 0644))
+	return destination
+}
+
+// verifyAllArgs returns the arguments verifying charts with helm-lint, the only mandatory check of the community
+// profile, followed by args.
+func verifyAllArgs(args ...string) []string {
+	return append([]string{"-e", "helm-lint", "-E", "--set", "profile.vendortype=community"}, args...)
+}
+
+func TestVerifyAll(t *testing.T) {
+
+	t.Run("Should fail when no chart is found", func(t *testing.T) {
+		cmd := NewVerifyAllCmd(viper.New())
+		cmd.SetOut(bytes.NewBufferString(""))
+		cmd.SetErr(bytes.NewBufferString(""))
+		cmd.SetArgs([]string{"-E", t.TempDir()})
+
+		require.EqualError(t, cmd.Execute(), "no charts found in "+cmd.Flags().Arg(0))
+	})
+
+	t.Run("Should verify each chart and fail when a mandatory check fails", func(t *testing.T) {
+		chartsDir := t.TempDir()
+		reportDir := t.TempDir()
+		valid := copyTestChart(t, "../internal/chartverifier/checks/chart-0.1.0-v3.valid.tgz", chartsDir)
+		lintError := copyTestChart(t, "../internal/chartverifier/checks/chart-0.1.0-v2.lint-error.tgz", chartsDir)
+
+		cmd := NewVerifyAllCmd(viper.New())
+		cmd.SetErr(bytes.NewBufferString(""))
+		outBuf := bytes.NewBufferString("")
+		utils.CmdStdout = outBuf
+		cmd.SetArgs(verifyAllArgs("--concurrency", "2", "--report-dir", reportDir, chartsDir))
+
+		err := cmd.Execute()
+		require.EqualError(t, err, "1 of 2 charts failed verification")
+		require.Equal(t, exitMandatoryFailed, getExitCode(err))
+
+		summary := verifyAllSummary{}
+		require.NoError(t, yaml.Unmarshal(outBuf.Bytes(), &summary))
+		require.Equal(t, 1, summary.Passed)
+		require.Equal(t, 1, summary.Failed)
+		require.Len(t, summary.Charts, 2)
+
+		require.Equal(t, lintError, summary.Charts[0].ChartUri)
+		require.Equal(t, "testchart", summary.Charts[0].Name)
+		require.False(t, summary.Charts[0].Passed)
+		require.Equal(t, []apiChecks.CheckName{"v1.0/helm-lint"}, summary.Charts[0].FailedMandatoryChecks)
+
+		require.Equal(t, valid, summary.Charts[1].ChartUri)
+		require.Equal(t, "chart", summary.Charts[1].Name)
+		require.Equal(t, "0.1.0-v3.valid", summary.Charts[1].Version)
+		require.True(t, summary.Charts[1].Passed)
+		require.Equal(t, filepath.Join(reportDir, "chart", "0.1.0-v3.valid", "report.yaml"), summary.Charts[1].Report)
+		require.FileExists(t, summary.Charts[1].Report)
+		require.FileExists(t, filepath.Join(reportDir, "testchart", "0.1.0", "report.yaml"))
+		require.FileExists(t, filepath.Join(reportDir, "summary.yaml"))
+	})
+
+	t.Run("Should write the reports of charts with the same name and version to distinct files", func(t *testing.T) {
+		chartsDir := t.TempDir()
+		reportDir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(chartsDir, "a"), 0755))
+		require.NoError(t, os.Mkdir(filepath.Join(chartsDir, "b"), 0755))
+		copyTestChart(t, "../internal/chartverifier/checks/chart-0.1.0-v3.valid.tgz", filepath.Join(chartsDir, "a"))
+		copyTestChart(t, "../internal/chartverifier/checks/chart-0.1.0-v3.valid.tgz", filepath.Join(chartsDir, "b"))
+
+		cmd := NewVerifyAllCmd(viper.New())
+		cmd.SetErr(bytes.NewBufferString(""))
+		outBuf := bytes.NewBufferString("")
+		utils.CmdStdout = outBuf
+		cmd.SetArgs(verifyAllArgs("--report-dir", reportDir, chartsDir))
+		require.NoError(t, cmd.Execute())
+
+		summary := verifyAllSummary{}
+		require.NoError(t, yaml.Unmarshal(outBuf.Bytes(), &summary))
+		require.Len(t, summary.Charts, 2)
+		require.NotEqual(t, summary.Charts[0].Report, summary.Charts[1].Report)
+		for _, chart := range summary.Charts {
+			require.Equal(t, filepath.Join(reportDir, "chart", "0.1.0-v3.valid"), filepath.Dir(chart.Report))
+			require.FileExists(t, chart.Report)
+		}
+	})
+
+	t.Run("Should pass a chart whose failures are accepted by the baseline", func(t *testing.T) {
+		chartsDir := t.TempDir()
+		reportDir := t.TempDir()
+		copyTestChart(t, "../internal/chartverifier/checks/chart-0.1.0-v2.lint-error.tgz", chartsDir)
+		baselineFile := filepath.Join(t.TempDir(), "baseline.yaml")
+		require.NoError(t, ioutil.WriteFile(baselineFile, []byte("accepted:\n  - check: helm-lint\n"), 0644))
+
+		cmd := NewVerifyAllCmd(viper.New())
+		cmd.SetErr(bytes.NewBufferString(""))
+		outBuf := bytes.NewBufferString("")
+		utils.CmdStdout = outBuf
+		cmd.SetArgs(verifyAllArgs("--baseline", baselineFile, "--report-dir", reportDir, chartsDir))
+
+		require.NoError(t, cmd.Execute())
+
+		summary := verifyAllSummary{}
+		require.NoError(t, yaml.Unmarshal(outBuf.Bytes(), &summary))
+		require.True(t, summary.Charts[0].Passed)
+
+		content, err := ioutil.ReadFile(summary.Charts[0].Report)
+		require.NoError(t, err)
+		report, err := apiReport.NewReport().SetContent(string(content)).Load()
+		require.NoError(t, err)
+		require.Equal(t, apiReport.AcceptedOutcomeType, report.Results[0].Outcome)
+		require.NotEmpty(t, report.Results[0].Fingerprint)
+	})
+
+	t.Run("Should record charts which cannot be verified", func(t *testing.T) {
+		chartsDir := t.TempDir()
+		copyTestChart(t, "../internal/chartverifier/checks/chart-0.1.0-v3.valid.tgz", chartsDir)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(chartsDir, "broken-0.1.0.tgz"), []byte("not a chart"), 0644))
+
+		cmd := NewVerifyAllCmd(viper.New())
+		cmd.SetErr(bytes.NewBufferString(""))
+		outBuf := bytes.NewBufferString("")
+		utils.CmdStdout = outBuf
+		cmd.SetArgs(verifyAllArgs("--report-dir", t.TempDir(), chartsDir))
+
+		err := cmd.Execute()
+		require.EqualError(t, err, "1 of 2 charts failed verification")
+		require.Equal(t, exitToolError, getExitCode(err))
+
+		summary := verifyAllSummary{}
+		require.NoError(t, yaml.Unmarshal(outBuf.Bytes(), &summary))
+		require.Equal(t, "broken-0.1.0.tgz", summary.Charts[0].Name)
+		require.NotEmpty(t, summary.Charts[0].Error)
+		require.True(t, summary.Charts[1].Passed)
+	})
+}
+
+// newChangedChartsRepository creates a git repository with the valid chart committed, and the chart failing helm lint
+// added to the working tree.
+func newChangedChartsRepository(t *testing.T) (string, string) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	copyTestChart(t, "../internal/chartverifier/checks/chart-0.1.0-v3.valid.tgz", dir)
+	_, err = worktree.Add(".")
+	require.NoError(t, err)
+	_, err = worktree.Commit("Add chart", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+	lintError := copyTestChart(t, "../internal/chartverifier/checks/chart-0.1.0-v2.lint-error.tgz", dir)
+	return dir, lintError
+}
+
+func TestVerifyChangedCharts(t *testing.T) {
+
+	t.Run("verify-all should only verify the changed charts", func(t *testing.T) {
+		repoDir, lintError := newChangedChartsRepository(t)
+
+		cmd := NewVerifyAllCmd(viper.New())
+		cmd.SetErr(bytes.NewBufferString(""))
+		outBuf := bytes.NewBufferString("")
+		utils.CmdStdout = outBuf
+		cmd.SetArgs(verifyAllArgs("--report-dir", t.TempDir(), "--changed-since", "HEAD", repoDir))
+
+		require.EqualError(t, cmd.Execute(), "1 of 1 charts failed verification")
+
+		summary := verifyAllSummary{}
+		require.NoError(t, yaml.Unmarshal(outBuf.Bytes(), &summary))
+		require.Len(t, summary.Charts, 1)
+		require.Equal(t, lintError, summary.Charts[0].ChartUri)
+	})
+
+	t.Run("verify should evaluate each changed chart under the --fail-on policy", func(t *testing.T) {
+		repoDir, _ := newChangedChartsRepository(t)
+
+		currentDir, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(t.TempDir()))
+		t.Cleanup(func() { require.NoError(t, os.Chdir(currentDir)) })
+
+		for failOn, exitCode := range map[string]int{failOnNone: exitPassed, failOnMandatory: exitMandatoryFailed} {
+			cmd := NewVerifyCmd(viper.New())
+			cmd.SetErr(bytes.NewBufferString(""))
+			outBuf := bytes.NewBufferString("")
+			utils.CmdStdout = outBuf
+			cmd.SetArgs(verifyAllArgs("--fail-on", failOn, "--changed-since", "HEAD", repoDir))
+
+			require.Equal(t, exitCode, getExitCode(cmd.Execute()), failOn)
+
+			summary := verifyAllSummary{}
+			require.NoError(t, yaml.Unmarshal(outBuf.Bytes(), &summary))
+			require.Equal(t, 1, summary.Failed)
+		}
+	})
+
+	t.Run("verify should write the reports of the changed charts to --report-dir", func(t *testing.T) {
+		repoDir, _ := newChangedChartsRepository(t)
+		reportDir := t.TempDir()
+
+		cmd := NewVerifyCmd(viper.New())
+		cmd.SetErr(bytes.NewBufferString(""))
+		utils.CmdStdout = bytes.NewBufferString("")
+		cmd.SetArgs(verifyAllArgs("--fail-on", failOnNone, "--concurrency", "2", "--report-dir", reportDir, "--changed-since", "HEAD", repoDir))
+
+		require.NoError(t, cmd.Execute())
+		require.FileExists(t, filepath.Join(reportDir, "summary.yaml"))
+		require.FileExists(t, filepath.Join(reportDir, "testchart", "0.1.0", "report.yaml"))
+	})
+
+	t.Run("verify should reject the flags which cannot be used with or without --changed-since", func(t *testing.T) {
+		repoDir, lintError := newChangedChartsRepository(t)
+
+		cmd := NewVerifyCmd(viper.New())
+		cmd.SetOut(bytes.NewBufferString(""))
+		cmd.SetErr(bytes.NewBufferString(""))
+		cmd.SetArgs(verifyAllArgs("-w", "--changed-since", "HEAD", repoDir))
+		require.EqualError(t, cmd.Execute(), "--write-to-file cannot be used with --changed-since, the reports are written to --report-dir")
+
+		cmd = NewVerifyCmd(viper.New())
+		cmd.SetOut(bytes.NewBufferString(""))
+		cmd.SetErr(bytes.NewBufferString(""))
+		cmd.SetArgs(verifyAllArgs("--report-dir", t.TempDir(), lintError))
+		require.EqualError(t, cmd.Execute(), "--report-dir can only be used with --changed-since")
+	})
+
+	t.Run("verify should succeed when no chart changed", func(t *testing.T) {
+		repoDir, lintError := newChangedChartsRepository(t)
+		require.NoError(t, os.Remove(lintError))
+
+		currentDir, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(t.TempDir()))
+		t.Cleanup(func() { require.NoError(t, os.Chdir(currentDir)) })
+
+		cmd := NewVerifyCmd(viper.New())
+		cmd.SetErr(bytes.NewBufferString(""))
+		outBuf := bytes.NewBufferString("")
+		utils.CmdStdout = outBuf
+		cmd.SetArgs(verifyAllArgs("--changed-since", "HEAD", repoDir))
+
+		require.NoError(t, cmd.Execute())
+
+		summary := verifyAllSummary{}
+		require.NoError(t, yaml.Unmarshal(outBuf.Bytes(), &summary))
+		require.Empty(t, summary.Charts)
+		require.FileExists(t, filepath.Join("chartverifier", "summary.yaml"))
+	})
+}
+
+func TestVerifyCharts(t *testing.T) {
+
+	var mutex sync.Mutex
+	running, maxRunning := 0, 0
+	verify := func(chartUri string) *chartSummary {
+		mutex.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mutex.Lock()
+		running--
+		mutex.Unlock()
+		return &chartSummary{ChartUri: chartUri, Passed: chartUri != "b"}
+	}
+
+	summary := verifyCharts([]string{"a", "b", "c", "d", "e"}, 2, verify)
+	require.Equal(t, 2, maxRunning)
+	require.Equal(t, 4, summary.Passed)
+	require.Equal(t, 1, summary.Failed)
+	for index, chartUri := range []string{"a", "b", "c", "d", "e"} {
+		require.Equal(t, chartUri, summary.Charts[index].ChartUri)
+	}
+}
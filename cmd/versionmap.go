@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/redhat-certification/chart-verifier/internal/tool"
+)
+
+func init() {
+	rootCmd.AddCommand(newVersionMapCmd())
+}
+
+type versionMapUpdateOptions struct {
+	// source is the file or url of the release metadata the map is generated from
+	source string
+	// versionMap is the file or url of the map whose kind node images are kept, the embedded map if not set
+	versionMap string
+	// mapVersion is the version of the generated map
+	mapVersion string
+	// output is the file the map is written to, stdout if not set
+	output string
+}
+
+func newVersionMapCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "version-map",
+		Short: "Manages the Kubernetes to OpenShift version map",
+	}
+	cmd.AddCommand(newVersionMapUpdateCmd())
+	return cmd
+}
+
+func newVersionMapUpdateCmd() *cobra.Command {
+	opts := &versionMapUpdateOptions{}
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Regenerates the Kubernetes to OpenShift version map from release metadata",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runVersionMapUpdate(opts, cmd.OutOrStdout())
+		},
+	}
+	cmd.Flags().StringVar(&opts.source, "source", "", "file or url of the release metadata listing the OpenShift releases and their Kubernetes version")
+	cmd.Flags().StringVar(&opts.versionMap, "version-map", "", "file or url of the version map whose kind node images are kept (default: the map embedded in chart-verifier)")
+	cmd.Flags().StringVar(&opts.mapVersion, "map-version", time.Now().Format("2006.01.02"), "version of the generated map, recorded in the reports")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "file the generated map is written to (default: stdout)")
+	_ = cmd.MarkFlagRequired("source")
+	return cmd
+}
+
+func runVersionMapUpdate(opts *versionMapUpdateOptions, out io.Writer) error {
+	if len(opts.versionMap) > 0 {
+		if err := tool.LoadVersionMap(opts.versionMap); err != nil {
+			return err
+		}
+	}
+	metadata, err := tool.ReadURI(opts.source)
+	if err != nil {
+		return fmt.Errorf("error reading release metadata %s: %v", opts.source, err)
+	}
+	versionMap, err := tool.GenerateVersionMap(metadata, opts.mapVersion)
+	if err != nil {
+		return err
+	}
+	if len(opts.output) == 0 {
+		_, err = out.Write(versionMap)
+		return err
+	}
+	// #nosec G306
+	return ioutil.WriteFile(opts.output, versionMap, 0644)
+}
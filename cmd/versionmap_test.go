@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const versionMapReleaseMetadata = `releases:
+  - openshift-version: 4.13.0
+    kube-version: v1.26.3
+  - openshift-version: 4.12.1
+    kube-version: v1.25.4
+`
+
+func TestVersionMapUpdate(t *testing.T) {
+
+	source := path.Join(t.TempDir(), "releases.yaml")
+	require.NoError(t, ioutil.WriteFile(source, []byte(versionMapReleaseMetadata), 0600))
+
+	t.Run("Map is written to stdout", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, runVersionMapUpdate(&versionMapUpdateOptions{source: source, mapVersion: "2023.05.01"}, &out))
+		require.Equal(t, `version: 2023.05.01
+versions:
+    - kube-version: "1.26"
+      ocp-version: "4.13"
+    - kube-version: "1.25"
+      ocp-version: "4.12"
+      kind-node-image: kindest/node:v1.25.3
+`, out.String())
+	})
+
+	t.Run("Map is written to the output file", func(t *testing.T) {
+		output := path.Join(t.TempDir(), "kubeOpenShiftVersionMap.yaml")
+		var out bytes.Buffer
+		require.NoError(t, runVersionMapUpdate(&versionMapUpdateOptions{source: source, mapVersion: "2023.05.01", output: output}, &out))
+		require.Empty(t, out.String())
+		versionMap, err := ioutil.ReadFile(output)
+		require.NoError(t, err)
+		require.Contains(t, string(versionMap), "version: 2023.05.01")
+	})
+
+	t.Run("Missing release metadata", func(t *testing.T) {
+		var out bytes.Buffer
+		err := runVersionMapUpdate(&versionMapUpdateOptions{source: path.Join(t.TempDir(), "missing.yaml")}, &out)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error reading release metadata")
+	})
+}
@@ -28,11 +28,15 @@ type RunOptions struct {
 	OpenShiftVersion   string
 	WebCatalogOnly     bool
 	SuppressErrorLog   bool
+	KeepOnFailure      bool
+	EphemeralCluster   bool
 	ClientTimeout      time.Duration
 	HelmInstallTimeout time.Duration
 	ChartUri           string
 	Settings           *cli.EnvSettings
 	PublicKeys         []string
+	VersionMap         string
+	Baseline           string
 }
 
 func Run(options RunOptions) (*apireport.Report, error) {
@@ -65,8 +69,12 @@ func Run(options RunOptions) (*apireport.Report, error) {
 		SetWebCatalogOnly(options.WebCatalogOnly).
 		SetTimeout(options.ClientTimeout).
 		SetHelmInstallTimeout(options.HelmInstallTimeout).
+		SetKeepOnFailure(options.KeepOnFailure).
+		SetEphemeralCluster(options.EphemeralCluster).
 		SetSettings(options.Settings).
 		SetPublicKeys(options.PublicKeys).
+		SetVersionMap(options.VersionMap).
+		SetBaseline(options.Baseline).
 		Build()
 
 	if err != nil {
@@ -0,0 +1,168 @@
+package chartverifier
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/redhat-certification/chart-verifier/internal/tool"
+	apiChecks "github.com/redhat-certification/chart-verifier/pkg/chartverifier/checks"
+	apiReport "github.com/redhat-certification/chart-verifier/pkg/chartverifier/report"
+)
+
+const (
+	BaselineAccepted = "Accepted by baseline"
+	BaselineExpired  = "Baseline acceptance expired"
+	BaselineRefused  = "Baseline acceptance refused, the failure of a mandatory check of a certification profile cannot be accepted"
+
+	baselineDateFormat = "2006-01-02"
+)
+
+var (
+	// durationPattern matches durations, such as the 4s of a test hook which succeeded in 4s.
+	durationPattern = regexp.MustCompile(`\b(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+\b`)
+	// generatedNamePattern matches the random parts chart testing adds to the names of its releases and namespaces.
+	generatedNamePattern = regexp.MustCompile(`build-[0-9a-z]{6}\b|-[0-9a-z]{10}\b`)
+)
+
+// Baseline lists the known check failures which are accepted, a failure it matches being reported as accepted
+// rather than failed.
+type Baseline struct {
+	Accepted []*BaselineEntry `yaml:"accepted"`
+
+	uri    string
+	digest string
+}
+
+// BaselineEntry is an entry of a baseline, with its reason pattern and expiry date parsed.
+type BaselineEntry struct {
+	apiReport.BaselineEntry `yaml:",inline"`
+
+	reasonPattern *regexp.Regexp
+	expiryDate    time.Time
+}
+
+// LoadBaseline reads a baseline from a file or url.
+func LoadBaseline(uri string) (*Baseline, error) {
+	content, err := tool.ReadURI(uri)
+	if err != nil {
+		return nil, fmt.Errorf("error reading baseline %s: %v", uri, err)
+	}
+	baseline := &Baseline{uri: uri, digest: fmt.Sprintf("sha256:%x", sha256.Sum256(content))}
+	if err = yaml.Unmarshal(content, baseline); err != nil {
+		return nil, fmt.Errorf("error parsing baseline %s: %v", uri, err)
+	}
+	for index, entry := range baseline.Accepted {
+		if len(entry.Check) == 0 {
+			return nil, fmt.Errorf("error in baseline %s: entry %d has no check", uri, index+1)
+		}
+		if len(entry.Reason) > 0 {
+			if entry.reasonPattern, err = regexp.Compile(entry.Reason); err != nil {
+				return nil, fmt.Errorf("error in baseline %s: reason of %s: %v", uri, entry.Check, err)
+			}
+		}
+		if len(entry.Expires) > 0 {
+			if entry.expiryDate, err = time.Parse(baselineDateFormat, entry.Expires); err != nil {
+				return nil, fmt.Errorf("error in baseline %s: expiry date of %s: %v", uri, entry.Check, err)
+			}
+		}
+	}
+	return baseline, nil
+}
+
+// GetFingerprint returns the fingerprint of the failure of a check, from the name of the check and the parts of the
+// reason it failed which are the same from one run to the next: durations and the random parts of the names of the
+// releases and namespaces of chart testing are left out.
+func GetFingerprint(checkReport *apiReport.CheckReport) string {
+	reason := durationPattern.ReplaceAllString(checkReport.Reason, "<duration>")
+	reason = generatedNamePattern.ReplaceAllStringFunc(reason, func(name string) string {
+		// a word of the chart, such as -deployment, is not random.
+		if strings.HasPrefix(name, "build-") || strings.ContainsAny(name, "0123456789") {
+			return "-<generated>"
+		}
+		return name
+	})
+	return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(fmt.Sprintf("%s\n%s", checkReport.Check, reason))))
+}
+
+// GetMetadata returns the uri and digest of the baseline, with the entries of it which accepted a failure.
+func (b *Baseline) GetMetadata(applied []*BaselineEntry) *apiReport.BaselineMetadata {
+	metadata := &apiReport.BaselineMetadata{Uri: b.uri, Digest: b.digest}
+	for _, entry := range applied {
+		metadata.Applied = append(metadata.Applied, &entry.BaselineEntry)
+	}
+	return metadata
+}
+
+// Apply reports a failed check as accepted if an entry of the baseline accepts it at the time given, and returns the
+// entry. A failure matching only entries which expired stays failed, with the expiry added to its reason, and so does
+// the failure of a mandatory check unless acceptMandatory is set.
+func (b *Baseline) Apply(checkReport *apiReport.CheckReport, now time.Time, acceptMandatory bool) *BaselineEntry {
+	if checkReport.Outcome != apiReport.FailOutcomeType {
+		return nil
+	}
+	fingerprint := checkReport.Fingerprint
+	if len(fingerprint) == 0 {
+		fingerprint = GetFingerprint(checkReport)
+	}
+
+	var expired *BaselineEntry
+	for _, entry := range b.Accepted {
+		if !entry.matches(checkReport, fingerprint) {
+			continue
+		}
+		if entry.isExpired(now) {
+			expired = entry
+			continue
+		}
+		if checkReport.Type == apiChecks.MandatoryCheckType && !acceptMandatory {
+			checkReport.Reason = addReason(checkReport.Reason, BaselineRefused)
+			return nil
+		}
+		checkReport.Outcome = apiReport.AcceptedOutcomeType
+		checkReport.Reason = addReason(checkReport.Reason, entry.acceptedReason())
+		return entry
+	}
+	if expired != nil {
+		checkReport.Reason = addReason(checkReport.Reason, fmt.Sprintf("%s on %s", BaselineExpired, expired.Expires))
+	}
+	return nil
+}
+
+func (e *BaselineEntry) matches(checkReport *apiReport.CheckReport, fingerprint string) bool {
+	check := string(checkReport.Check)
+	if e.Check != check && !strings.HasSuffix(check, "/"+e.Check) {
+		return false
+	}
+	if len(e.Fingerprint) > 0 && e.Fingerprint != fingerprint {
+		return false
+	}
+	return e.reasonPattern == nil || e.reasonPattern.MatchString(checkReport.Reason)
+}
+
+// isExpired returns true once the last day the failure is accepted has passed.
+func (e *BaselineEntry) isExpired(now time.Time) bool {
+	return !e.expiryDate.IsZero() && !now.UTC().Before(e.expiryDate.AddDate(0, 0, 1))
+}
+
+func (e *BaselineEntry) acceptedReason() string {
+	reason := BaselineAccepted
+	if len(e.Expires) > 0 {
+		reason = fmt.Sprintf("%s until %s", reason, e.Expires)
+	}
+	if len(e.Comment) > 0 {
+		reason = fmt.Sprintf("%s : %s", reason, e.Comment)
+	}
+	return reason
+}
+
+func addReason(reason string, addition string) string {
+	if len(reason) == 0 {
+		return addition
+	}
+	return fmt.Sprintf("%s\n%s", strings.TrimRight(reason, "\n"), addition)
+}
@@ -0,0 +1,144 @@
+package chartverifier
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	apiChecks "github.com/redhat-certification/chart-verifier/pkg/chartverifier/checks"
+	apiReport "github.com/redhat-certification/chart-verifier/pkg/chartverifier/report"
+)
+
+const testBaseline = `accepted:
+  - check: images-are-certified
+    reason: "registry\\.example\\.com"
+    comment: images are certified by the partner
+  - check: v1.0/helm-lint
+    expires: 2023-06-30
+  - check: v1.0/has-readme
+    fingerprint: %s
+`
+
+func newFailedCheck(check string, reason string) *apiReport.CheckReport {
+	checkReport := &apiReport.CheckReport{Check: apiChecks.CheckName(check), Outcome: apiReport.FailOutcomeType, Reason: reason}
+	checkReport.Fingerprint = GetFingerprint(checkReport)
+	return checkReport
+}
+
+func loadTestBaseline(t *testing.T, content string) (*Baseline, error) {
+	baselineFile := filepath.Join(t.TempDir(), "baseline.yaml")
+	writeTestFile(t, baselineFile, content)
+	return LoadBaseline(baselineFile)
+}
+
+func TestLoadBaseline(t *testing.T) {
+
+	baseline, err := loadTestBaseline(t, fmt.Sprintf(testBaseline, "sha256:0123"))
+	require.NoError(t, err)
+	require.Len(t, baseline.Accepted, 3)
+	require.Equal(t, "2023-06-30", baseline.Accepted[1].Expires)
+
+	_, err = loadTestBaseline(t, "accepted:\n  - reason: lint\n")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "entry 1 has no check")
+
+	_, err = loadTestBaseline(t, "accepted:\n  - check: helm-lint\n    reason: \"[\"\n")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "reason of helm-lint")
+
+	_, err = loadTestBaseline(t, "accepted:\n  - check: helm-lint\n    expires: 30/06/2023\n")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expiry date of helm-lint")
+
+	_, err = LoadBaseline(filepath.Join(t.TempDir(), "does-not-exist.yaml"))
+	require.Error(t, err)
+}
+
+func TestApplyBaseline(t *testing.T) {
+
+	readme := newFailedCheck("v1.0/has-readme", "Chart does not have a README")
+	baseline, err := loadTestBaseline(t, fmt.Sprintf(testBaseline, readme.Fingerprint))
+	require.NoError(t, err)
+	beforeExpiry := time.Date(2023, 6, 30, 23, 0, 0, 0, time.UTC)
+	afterExpiry := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Check without version and reason pattern", func(t *testing.T) {
+		checkReport := newFailedCheck("v1.1/images-are-certified", "Image is not Red Hat certified : registry.example.com/app:1.0")
+		baseline.Apply(checkReport, afterExpiry, true)
+		require.Equal(t, apiReport.AcceptedOutcomeType, checkReport.Outcome)
+		require.Equal(t, "Image is not Red Hat certified : registry.example.com/app:1.0\nAccepted by baseline : images are certified by the partner", checkReport.Reason)
+
+		checkReport = newFailedCheck("v1.1/images-are-certified", "Image is not Red Hat certified : quay.io/app:1.0")
+		baseline.Apply(checkReport, afterExpiry, true)
+		require.Equal(t, apiReport.FailOutcomeType, checkReport.Outcome)
+	})
+
+	t.Run("Expiry date", func(t *testing.T) {
+		checkReport := newFailedCheck("v1.0/helm-lint", "Helm lint has failed")
+		baseline.Apply(checkReport, beforeExpiry, true)
+		require.Equal(t, apiReport.AcceptedOutcomeType, checkReport.Outcome)
+		require.Equal(t, "Helm lint has failed\nAccepted by baseline until 2023-06-30", checkReport.Reason)
+
+		checkReport = newFailedCheck("v1.0/helm-lint", "Helm lint has failed")
+		baseline.Apply(checkReport, afterExpiry, true)
+		require.Equal(t, apiReport.FailOutcomeType, checkReport.Outcome)
+		require.Equal(t, "Helm lint has failed\nBaseline acceptance expired on 2023-06-30", checkReport.Reason)
+	})
+
+	t.Run("Fingerprint", func(t *testing.T) {
+		baseline.Apply(readme, afterExpiry, true)
+		require.Equal(t, apiReport.AcceptedOutcomeType, readme.Outcome)
+
+		checkReport := newFailedCheck("v1.0/has-readme", "README is empty")
+		baseline.Apply(checkReport, afterExpiry, true)
+		require.Equal(t, apiReport.FailOutcomeType, checkReport.Outcome)
+	})
+
+	t.Run("Mandatory checks of a certification profile", func(t *testing.T) {
+		checkReport := newFailedCheck("v1.0/helm-lint", "Helm lint has failed")
+		checkReport.Type = apiChecks.MandatoryCheckType
+		require.Nil(t, baseline.Apply(checkReport, beforeExpiry, false))
+		require.Equal(t, apiReport.FailOutcomeType, checkReport.Outcome)
+		require.Equal(t, "Helm lint has failed\n"+BaselineRefused, checkReport.Reason)
+
+		checkReport = newFailedCheck("v1.0/helm-lint", "Helm lint has failed")
+		checkReport.Type = apiChecks.MandatoryCheckType
+		require.Equal(t, baseline.Accepted[1], baseline.Apply(checkReport, beforeExpiry, true))
+		require.Equal(t, apiReport.AcceptedOutcomeType, checkReport.Outcome)
+	})
+
+	t.Run("Checks which did not fail", func(t *testing.T) {
+		checkReport := &apiReport.CheckReport{Check: "v1.0/helm-lint", Outcome: apiReport.PassOutcomeType, Reason: "Helm lint successful"}
+		baseline.Apply(checkReport, beforeExpiry, true)
+		require.Equal(t, apiReport.PassOutcomeType, checkReport.Outcome)
+		require.Equal(t, "Helm lint successful", checkReport.Reason)
+	})
+}
+
+func TestGetBaselineMetadata(t *testing.T) {
+
+	baseline, err := loadTestBaseline(t, fmt.Sprintf(testBaseline, "sha256:0123"))
+	require.NoError(t, err)
+
+	metadata := baseline.GetMetadata([]*BaselineEntry{baseline.Accepted[1]})
+	require.Equal(t, "baseline.yaml", filepath.Base(metadata.Uri))
+	require.Regexp(t, "^sha256:[0-9a-f]{64}$", metadata.Digest)
+	require.Equal(t, []*apiReport.BaselineEntry{{Check: "v1.0/helm-lint", Expires: "2023-06-30"}}, metadata.Applied)
+
+	require.Empty(t, baseline.GetMetadata(nil).Applied)
+}
+
+func TestGetFingerprint(t *testing.T) {
+
+	getFingerprint := func(reason string) string {
+		return GetFingerprint(&apiReport.CheckReport{Check: "v1.0/chart-testing", Reason: reason})
+	}
+
+	fingerprint := getFingerprint("Chart test hook mychart-k3j2h1g0fd-test : Failed in 4s\nnamespace mychart-build-x1y2z3-k3j2h1g0fd")
+	require.Equal(t, fingerprint, getFingerprint("Chart test hook mychart-0a1b2c3d4e-test : Failed in 1m2.5s\nnamespace mychart-build-abc123-0a1b2c3d4e"))
+	require.NotEqual(t, fingerprint, getFingerprint("Chart test hook mychart-k3j2h1g0fd-test : Succeeded in 4s\nnamespace mychart-build-x1y2z3-k3j2h1g0fd"))
+	require.NotEqual(t, getFingerprint("Deployment mychart-deployment is not ready"), getFingerprint("Deployment mychart-controller is not ready"))
+}
@@ -0,0 +1,89 @@
+package checks
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/chart"
+
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier/utils"
+)
+
+//go:embed openshiftCategories.yaml
+var categoriesContent embed.FS
+
+const (
+	// CategoriesConfigName is the key of the check configuration used to override the OpenShift categories.
+	CategoriesConfigName = "categories"
+
+	ProviderAnnotation     = "charts.openshift.io/provider"
+	ProviderTypeAnnotation = "charts.openshift.io/providerType"
+
+	PartnerProviderType   = "partner"
+	RedHatProviderType    = "redhat"
+	CommunityProviderType = "community"
+)
+
+// Based on the categories of the OpenShift developer catalog
+var openshiftCategories []string
+
+type categoriesList struct {
+	Categories []string `yaml:"categories"`
+}
+
+func init() {
+	yamlFile, err := categoriesContent.ReadFile("openshiftCategories.yaml")
+	if err != nil {
+		utils.LogError(fmt.Sprintf("Error reading content of openshiftCategories.yaml: %v", err))
+		return
+	}
+
+	categories := categoriesList{}
+	err = yaml.Unmarshal(yamlFile, &categories)
+	if err != nil {
+		utils.LogError(fmt.Sprintf("Error reading content of openshiftCategories.yaml: %v", err))
+		return
+	}
+	openshiftCategories = categories.Categories
+}
+
+// getOpenShiftCategories returns the categories set in the check configuration, or the embedded OpenShift categories if
+// none are set.
+func getOpenShiftCategories(opts *CheckOptions) []string {
+	if opts.ViperConfig != nil {
+		if categories := opts.ViperConfig.GetStringSlice(CategoriesConfigName); len(categories) > 0 {
+			return categories
+		}
+	}
+	return openshiftCategories
+}
+
+// getOpenShiftCategoryKeywords returns the keywords of the chart which are OpenShift categories.
+func getOpenShiftCategoryKeywords(keywords []string, categories []string) []string {
+	categoryKeywords := make([]string, 0)
+	for _, keyword := range keywords {
+		for _, category := range categories {
+			if strings.EqualFold(strings.TrimSpace(keyword), strings.TrimSpace(category)) {
+				categoryKeywords = append(categoryKeywords, category)
+				break
+			}
+		}
+	}
+	return categoryKeywords
+}
+
+// getProviderType classifies the provider of a chart as partner, redhat or community. The providerType annotation is
+// used if set, otherwise a chart with a provider annotation is classified as a partner chart and a chart without one as
+// a community chart. The second value returned describes what the classification is based on.
+func getProviderType(c *chart.Chart) (string, string) {
+	annotations := c.Metadata.Annotations
+	if providerType, ok := annotations[ProviderTypeAnnotation]; ok && len(providerType) > 0 {
+		return strings.ToLower(providerType), fmt.Sprintf("annotation %s is %s", ProviderTypeAnnotation, providerType)
+	}
+	if provider, ok := annotations[ProviderAnnotation]; ok && len(provider) > 0 {
+		return PartnerProviderType, fmt.Sprintf("annotation %s is %s", ProviderAnnotation, provider)
+	}
+	return CommunityProviderType, fmt.Sprintf("annotations %s and %s are not set", ProviderTypeAnnotation, ProviderAnnotation)
+}
@@ -7,12 +7,15 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"sync"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/helm/chart-testing/v3/pkg/chart"
 	"github.com/helm/chart-testing/v3/pkg/config"
 	"github.com/helm/chart-testing/v3/pkg/util"
 	"github.com/imdario/mergo"
+	"github.com/mitchellh/copystructure"
 	"github.com/redhat-certification/chart-verifier/internal/chartverifier/utils"
 	"github.com/redhat-certification/chart-verifier/internal/tool"
 	"gopkg.in/yaml.v3"
@@ -22,31 +25,40 @@ import (
 
 const (
 	ReleaseConfigString string = "release"
+	// ConcurrencyConfigName is the key of the check configuration setting how many values files are installed and
+	// tested at the same time, each in a release of its own.
+	ConcurrencyConfigName string = "concurrency"
+
+	diagnosticsTimeout = 2 * time.Minute
+	cleanupTimeout     = 5 * time.Minute
+	clusterInfoTimeout = time.Minute
 )
 
-// Versioner provides OpenShift version
-type Versioner func(envSettings *cli.EnvSettings) (string, error)
+// Versioner provides the description of the cluster, its OpenShift version included
+type Versioner func(envSettings *cli.EnvSettings) (*tool.ClusterInfo, error)
 
-func getVersion(envSettings *cli.EnvSettings) (string, error) {
+func getVersion(envSettings *cli.EnvSettings) (*tool.ClusterInfo, error) {
 	kubeConfig := tool.GetClientConfig(envSettings)
 	kubectl, err := tool.NewKubectl(kubeConfig)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	serverVersion, err := kubectl.GetServerVersion()
-	if err != nil {
-		return "", err
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), clusterInfoTimeout)
+	defer cancel()
+	return kubectl.GetClusterInfo(ctx)
+}
 
-	// Relying on Kubernetes version can be replaced after fixing this issue:
-	// https://bugzilla.redhat.com/show_bug.cgi?id=1850656
-	kubeVersion := fmt.Sprintf("%s.%s", serverVersion.Major, serverVersion.Minor)
-	osVersion, ok := tool.GetKubeOpenShiftVersionMap()[kubeVersion]
+// getOpenShiftVersion returns the version of OpenShift the cluster runs or, for a cluster which is not OpenShift, the
+// OpenShift version of its Kubernetes version.
+func getOpenShiftVersion(clusterInfo *tool.ClusterInfo) (string, error) {
+	if clusterInfo.Distribution == tool.OpenShiftDistribution {
+		return clusterInfo.Version, nil
+	}
+	osVersion, ok := tool.GetKubeOpenShiftVersionMap()[clusterInfo.KubeVersion]
 	if !ok {
-		return "", fmt.Errorf("internal error: %q not found in Kubernetes-OpenShift version map", kubeVersion)
+		return "", fmt.Errorf("internal error: %q not found in Kubernetes-OpenShift version map", clusterInfo.KubeVersion)
 	}
-
 	return osVersion, nil
 }
 
@@ -102,25 +114,44 @@ func buildChartTestingConfiguration(opts *CheckOptions) config.Configuration {
 // interpretation the main logic chart-testing carries, and other
 // functions used in this context were also ported from
 // chart-verifier.
-func ChartTesting(opts *CheckOptions) (Result, error) {
+func ChartTesting(opts *CheckOptions) (r Result, err error) {
 
 	utils.LogInfo("Start chart install and test check")
 
+	// the ephemeral cluster is started before the timeout starts, so starting it does not use the time of the chart
+	// install and tests.
+	envSettings := opts.HelmEnvSettings
+	holder := opts.AnnotationHolder
+	if opts.EphemeralCluster {
+		cluster, err := startEphemeralCluster(opts.AnnotationHolder.GetCertifiedOpenShiftVersionFlag())
+		if err != nil {
+			utils.LogError("End chart install and test check with ephemeral cluster error")
+			return NewClusterUnavailableResult(err), nil
+		}
+		defer func() { stopEphemeralCluster(cluster, !r.Ok && opts.KeepOnFailure) }()
+		envSettings = getEphemeralEnvSettings(opts.HelmEnvSettings, cluster.KubeConfig)
+		holder = emulatedVersionHolder{opts.AnnotationHolder}
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
 	defer cancel()
 
 	cfg := buildChartTestingConfiguration(opts)
-	helm, err := tool.NewHelm(opts.HelmEnvSettings, opts.Values, opts.HelmInstallTimeout)
+	helm, err := tool.NewHelm(envSettings, opts.Values, opts.HelmInstallTimeout)
 	if err != nil {
 		utils.LogError("End chart install and test check with NewHelm error")
 		return NewResult(false, err.Error()), nil
 	}
 
-	kubeConfig := tool.GetClientConfig(opts.HelmEnvSettings)
+	kubeConfig := tool.GetClientConfig(envSettings)
 	kubectl, err := tool.NewKubectl(kubeConfig)
 	if err != nil {
 		utils.LogError("End chart install and test check with NewKubectl error")
-		return NewResult(false, err.Error()), nil
+		return NewClusterUnavailableResult(err), nil
+	}
+	if _, err = kubectl.GetServerVersion(); err != nil {
+		utils.LogError("End chart install and test check with GetServerVersion error")
+		return NewClusterUnavailableResult(err), nil
 	}
 
 	_, path, err := LoadChartFromURI(opts)
@@ -139,42 +170,62 @@ func ChartTesting(opts *CheckOptions) (Result, error) {
 	if len(configRelease) > 0 {
 		utils.LogInfo(fmt.Sprintf("User specifed release: %s", configRelease))
 	}
+	concurrency := getConcurrency(opts, configRelease)
 
+	upgradeReason := ""
+	upgraded := false
+	var testHooks []testHookResult
+	var leftovers []string
+	var scenarios []scenarioResult
 	if cfg.Upgrade {
-		oldChrt, err := getChartPreviousVersion(chrt)
-		if err != nil {
-			utils.LogError("End chart install and test check with getChartPreviousVersion error")
-			return NewResult(
-					false,
-					fmt.Sprintf("skipping upgrade test of '%s' because no previous chart is available", chrt.Yaml().Name)),
-				nil
-		}
-		breakingChangeAllowed, err := util.BreakingChangeAllowed(oldChrt.Yaml().Version, chrt.Yaml().Version)
-		if !breakingChangeAllowed {
-			utils.LogError("End chart install and test check with BreakingChangeAllowed not allowed")
-			return NewResult(
-					false,
-					fmt.Sprintf("Skipping upgrade test of '%s' because breaking changes are not allowed for chart", chrt)),
-				nil
+		oldChrt, err := getChartPreviousVersion(opts, chrt)
+		if err != nil && !IsNoPreviousVersion(err) {
+			utils.LogError(fmt.Sprintf("End chart install and test check with getChartPreviousVersion error: %v", err))
+			return NewResult(false, fmt.Sprintf("%s : previous version of '%s' is not available : %v", ChartUpgradeNotTested, chrt.Yaml().Name, err)), nil
 		} else if err != nil {
-			utils.LogError(fmt.Sprintf("End chart install and test check with BreakingChangeAllowed error: %v", err))
-			return NewResult(false, err.Error()), nil
+			utils.LogWarning(fmt.Sprintf("Skipping upgrade test of '%s': %v", chrt.Yaml().Name, err))
+			upgradeReason = fmt.Sprintf("%s : %v", ChartUpgradeNotTested, err)
+		} else {
+			upgradePath := fmt.Sprintf("%s to %s", oldChrt.Yaml().Version, chrt.Yaml().Version)
+			breakingChangeAllowed, err := util.BreakingChangeAllowed(oldChrt.Yaml().Version, chrt.Yaml().Version)
+			if breakingChangeAllowed {
+				utils.LogWarning(fmt.Sprintf("Skipping upgrade test of '%s' because breaking changes are allowed from %s", chrt.Yaml().Name, upgradePath))
+				upgradeReason = fmt.Sprintf("%s : breaking changes are allowed from %s", ChartUpgradeNotTested, upgradePath)
+			} else if err != nil {
+				utils.LogError(fmt.Sprintf("End chart install and test check with BreakingChangeAllowed error: %v", err))
+				return NewResult(false, err.Error()), nil
+			} else {
+				upgradeValues := opts.ViperConfig.GetStringMap(UpgradeValuesConfigName)
+				result := upgradeAndTestChart(ctx, cfg, oldChrt, chrt, helm, kubectl, opts.Values, upgradeValues, configRelease, opts.KeepOnFailure, concurrency)
+				if result.Error != nil {
+					utils.LogError(fmt.Sprintf("End chart install and test check with upgradeAndTestChart error: %v", result.Error))
+					r = addScenarioResults(NewResult(false, fmt.Sprintf("%s : %s : %v", ChartUpgradeFailed, upgradePath, result.Error)), result.Scenarios)
+					r = addTestHookResults(r, result.TestHooks)
+					return addCleanupLeftovers(r, result.CleanupLeftovers), nil
+				}
+				testHooks = result.TestHooks
+				leftovers = result.CleanupLeftovers
+				scenarios = result.Scenarios
+				upgraded = true
+				upgradeReason = fmt.Sprintf("%s : %s", ChartUpgradeTested, upgradePath)
+			}
 		}
-		result := upgradeAndTestChart(ctx, cfg, oldChrt, chrt, helm, kubectl, configRelease)
+	}
 
-		if result.Error != nil {
-			utils.LogError(fmt.Sprintf("End chart install and test check with upgradeAndTestChart error: %v", result.Error))
-			return NewResult(false, result.Error.Error()), nil
-		}
-	} else {
-		result := installAndTestChartRelease(ctx, cfg, chrt, helm, kubectl, opts.Values, configRelease)
+	if !upgraded {
+		result := installAndTestChartRelease(ctx, cfg, chrt, helm, kubectl, opts.Values, configRelease, opts.KeepOnFailure, concurrency)
 		if result.Error != nil {
 			utils.LogError(fmt.Sprintf("End chart install and test check with installAndTestChartRelease error: %v", result.Error))
-			return NewResult(false, result.Error.Error()), nil
+			r = addScenarioResults(NewResult(false, result.Error.Error()), result.Scenarios)
+			r = addTestHookResults(r, result.TestHooks)
+			return addCleanupLeftovers(r, result.CleanupLeftovers), nil
 		}
+		testHooks = result.TestHooks
+		leftovers = result.CleanupLeftovers
+		scenarios = result.Scenarios
 	}
 
-	if versionError := setOCVersion(opts.AnnotationHolder, opts.HelmEnvSettings, getVersion); versionError != nil {
+	if versionError := setOCVersion(holder, envSettings, getVersion); versionError != nil {
 		if versionError != nil {
 			utils.LogWarning(fmt.Sprintf("End chart install and test check with version error: %v", versionError))
 		}
@@ -182,19 +233,47 @@ func ChartTesting(opts *CheckOptions) (Result, error) {
 	}
 
 	utils.LogInfo("End chart install and test check")
-	return NewResult(true, ChartTestingSuccess), nil
+	r = NewResult(true, ChartTestingSuccess)
+	if len(upgradeReason) > 0 {
+		r.AddResult(true, upgradeReason)
+	}
+	r = addScenarioResults(r, scenarios)
+	return addCleanupLeftovers(addTestHookResults(r, testHooks), leftovers), nil
+}
+
+// getConcurrency returns how many values files are installed and tested at the same time. A release set in the check
+// configuration cannot be installed more than once at a time, so its values files are then tested one after the other.
+func getConcurrency(opts *CheckOptions, configRelease string) int {
+	concurrency := opts.ViperConfig.GetInt(ConcurrencyConfigName)
+	if concurrency > 1 && len(configRelease) > 0 {
+		utils.LogWarning(fmt.Sprintf("Values files are tested one after the other because release %s is set", configRelease))
+		return 1
+	}
+	if concurrency > 1 {
+		utils.LogInfo(fmt.Sprintf("Values files are tested %d at a time", concurrency))
+	}
+	return concurrency
+}
+
+// addCleanupLeftovers adds a warning for each object left over by the cleanup of the releases to the reason of the
+// chart-testing result, without changing its outcome.
+func addCleanupLeftovers(r Result, leftovers []string) Result {
+	for _, leftover := range leftovers {
+		r.AddResult(r.Ok, fmt.Sprintf("%s : %s", ChartCleanupLeftover, leftover))
+	}
+	return r
 }
 
 // generateInstallConfig extracts required information to install a
 // release and builds a clenup function to be used after tests are
-// executed.
+// executed, which returns the objects left over.
 func generateInstallConfig(
 	cfg config.Configuration,
 	chrt *chart.Chart,
 	helm *tool.Helm,
 	kubectl *tool.Kubectl,
 	configRelease string,
-) (namespace, release, releaseSelector string, cleanup func()) {
+) (namespace, release, releaseSelector string, cleanup func() []string) {
 	release = configRelease
 	if cfg.Namespace != "" {
 		namespace = cfg.Namespace
@@ -202,8 +281,8 @@ func generateInstallConfig(
 			release, _ = chrt.CreateInstallParams(cfg.BuildId)
 		}
 		releaseSelector = fmt.Sprintf("%s=%s", cfg.ReleaseLabel, release)
-		cleanup = func() {
-			helm.Uninstall(namespace, release)
+		cleanup = func() []string {
+			return uninstallRelease(helm, kubectl, namespace, release, false)
 		}
 	} else {
 		if len(release) == 0 {
@@ -211,37 +290,100 @@ func generateInstallConfig(
 		} else {
 			_, namespace = chrt.CreateInstallParams(cfg.BuildId)
 		}
-		cleanup = func() {
-			helm.Uninstall(namespace, release)
-			kubectl.DeleteNamespace(context.TODO(), namespace)
+		cleanup = func() []string {
+			return uninstallRelease(helm, kubectl, namespace, release, true)
 		}
 	}
 	return
 }
 
-// testRelease tests a release.
+// uninstallRelease uninstalls a release, then deletes what the release created and is left: hooks, resources kept by
+// their resource policy and objects whose deletion is pending, and the namespace of the release if it was generated
+// for it. It returns the objects left over once the cleanup timeout has expired.
+func uninstallRelease(helm *tool.Helm, kubectl *tool.Kubectl, namespace, release string, deleteNamespace bool) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cancel()
+
+	// the objects are listed before the release, and its record, is uninstalled.
+	objects, err := helm.GetReleaseObjects(namespace, release)
+	if err != nil {
+		utils.LogWarning(fmt.Sprintf("Error getting the objects of release %s: %v", release, err))
+	}
+	// the objects are deleted also when the uninstall fails, which is reported with the objects left over.
+	var leftovers []string
+	if err = helm.Uninstall(namespace, release); err != nil {
+		leftovers = append(leftovers, fmt.Sprintf("release %s: uninstall failed: %v", release, err))
+	}
+	if deleteNamespace {
+		objects = append(objects, tool.ReleaseObject{APIVersion: "v1", Kind: "Namespace", Name: namespace})
+	}
+	return append(leftovers, kubectl.DeleteObjects(ctx, objects)...)
+}
+
+// cleanupRelease is run once a release has been tested, it returns the objects left over by the cleanup. If the test
+// failed, the diagnostics of the release's namespace are written to a bundle next to the report, and the release is
+// kept when keepOnFailure is set.
+func cleanupRelease(kubectl *tool.Kubectl, namespace, release string, testErr error, keepOnFailure bool, cleanup func() []string) []string {
+	if testErr != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), diagnosticsTimeout)
+		defer cancel()
+		bundleName := fmt.Sprintf("diagnostics-%s-%s.tgz", release, time.Now().Format("01-02-2006-15-04-05"))
+		if bundle, err := utils.WriteArchive(bundleName, kubectl.CollectDiagnostics(ctx, namespace)); err != nil {
+			utils.LogWarning(fmt.Sprintf("Error writing diagnostics of release %s: %v", release, err))
+		} else {
+			utils.LogInfo(fmt.Sprintf("Diagnostics of release %s written to %s", release, bundle))
+		}
+		if keepOnFailure {
+			utils.LogWarning(fmt.Sprintf("Release %s in namespace %s has been kept for debugging, uninstall it when done", release, namespace))
+			return nil
+		}
+	}
+	return cleanup()
+}
+
+// testRelease tests a release and returns the outcome of its test hooks.
 func testRelease(
 	ctx context.Context,
 	helm *tool.Helm,
 	kubectl *tool.Kubectl,
 	release, namespace, releaseSelector string,
 	cleanupHelmTests bool,
-) error {
-	if err := kubectl.WaitForDeployments(ctx, namespace, releaseSelector); err != nil {
-		return err
+) ([]testHookResult, error) {
+	// the custom resources of the release are waited for too, if its objects can be listed.
+	objects, err := helm.GetReleaseObjects(namespace, release)
+	if err != nil {
+		utils.LogWarning(fmt.Sprintf("Custom resources of release %s are not waited for, its objects could not be listed: %v", release, err))
 	}
-	if err := helm.Test(ctx, namespace, release); err != nil {
-		return err
+	if err := kubectl.WaitForWorkloads(ctx, namespace, releaseSelector, objects); err != nil {
+		return nil, err
 	}
-	return nil
+	rel, testErr := helm.Test(ctx, namespace, release)
+	// the logs are fetched with a context of their own as the test may have failed because ctx expired.
+	logsCtx, cancel := context.WithTimeout(context.Background(), diagnosticsTimeout)
+	defer cancel()
+	return getTestHookResults(logsCtx, kubectl, namespace, rel), testErr
 }
 
-// getChartPreviousVersion attemtps to retrieve the previous version
-// of the given chart.
-func getChartPreviousVersion(chrt *chart.Chart) (*chart.Chart, error) {
-	// TODO: decide which sources do we consider when searching for a
-	//       previous version's candidate
-	return chrt, nil
+// getChartPreviousVersion retrieves the highest version of the given chart lower than its version, from the source of
+// released versions set in the check configuration. There is no previous version if the source is not set.
+func getChartPreviousVersion(opts *CheckOptions, chrt *chart.Chart) (*chart.Chart, error) {
+	source := opts.ViperConfig.GetString(UpgradeSourceConfigName)
+	if len(source) == 0 {
+		return nil, NoPreviousVersionErr(fmt.Sprintf("%s is not set", UpgradeSourceConfigName))
+	}
+
+	version, uri, cleanup, err := getPreviousChartURI(source, opts.HelmEnvSettings, chrt.Yaml().Name, chrt.Yaml().Version)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	utils.LogInfo(fmt.Sprintf("Previous version of chart %s: %s from %s", chrt.Yaml().Name, version, uri))
+
+	_, previousPath, err := LoadChartFromURI(&CheckOptions{URI: uri, HelmEnvSettings: opts.HelmEnvSettings})
+	if err != nil {
+		return nil, err
+	}
+	return chart.NewChart(previousPath)
 }
 
 // upgradeAndTestChart performs the installation of the given oldChrt,
@@ -252,59 +394,128 @@ func upgradeAndTestChart(
 	oldChrt, chrt *chart.Chart,
 	helm *tool.Helm,
 	kubectl *tool.Kubectl,
+	valuesOverrides map[string]interface{},
+	upgradeValuesOverrides map[string]interface{},
 	configRelease string,
-) chart.TestResult {
+	keepOnFailure bool,
+	concurrency int,
+) releaseTestResult {
 
 	// result contains the test result; please notice that each values
 	// file in the chart's 'ci' folder will be installed and tested
 	// and the first failure makes the test fail.
-	result := chart.TestResult{Chart: chrt}
-
-	valuesFiles := oldChrt.ValuesFilePathsForCI()
-	if len(valuesFiles) == 0 {
+	result := releaseTestResult{TestResult: chart.TestResult{Chart: chrt}}
+	// resultMutex guards the results of the scenarios run concurrently.
+	var resultMutex sync.Mutex
+
+	ciValuesFiles := oldChrt.ValuesFilePathsForCI()
+	valuesFiles := make([]string, 0, len(ciValuesFiles))
+	for _, valuesFile := range ciValuesFiles {
+		if cfg.SkipMissingValues && !chrt.HasCIValuesFile(valuesFile) {
+			// TODO: do not assume STDOUT here; instead a writer
+			//       should be given to be written to.
+			utils.LogWarning(fmt.Sprintf("Upgrade testing for values file '%s' skipped because a corresponding values file was not found in %s/ci", valuesFile, chrt.Path()))
+			continue
+		}
+		valuesFiles = append(valuesFiles, valuesFile)
+	}
+	if len(ciValuesFiles) == 0 {
 		valuesFiles = append(valuesFiles, "")
 	}
-	for _, valuesFile := range valuesFiles {
-		if valuesFile != "" {
-			if cfg.SkipMissingValues && !chrt.HasCIValuesFile(valuesFile) {
-				// TODO: do not assume STDOUT here; instead a writer
-				//       should be given to be written to.
-				utils.LogWarning(fmt.Sprintf("Upgrade testing for values file '%s' skipped because a corresponding values file was not found in %s/ci", valuesFile, chrt.Path()))
-				continue
-			}
-		}
 
-		// Use anonymous function. Otherwise deferred calls would pile up
-		// and be executed in reverse order after the loop.
-		fun := func() error {
-			namespace, release, releaseSelector, cleanup := generateInstallConfig(cfg, oldChrt, helm, kubectl, configRelease)
-			defer cleanup()
-
-			// Install previous version of chart. If installation fails, ignore this release.
-			if err := helm.Install(ctx, namespace, oldChrt.Path(), release, valuesFile); err != nil {
-				return fmt.Errorf("Upgrade testing for release '%s' skipped because of previous revision installation error: %w", release, err)
-			}
-			if err := testRelease(ctx, helm, kubectl, release, namespace, releaseSelector, true); err != nil {
-				return fmt.Errorf("Upgrade testing for release '%s' skipped because of previous revision testing error", release)
-			}
+	// Use a function per values file. Otherwise deferred calls would pile up
+	// and be executed in reverse order after the loop.
+	fun := func(valuesFile string) (err error) {
+		scenarioHelm, err := getScenarioHelm(helm, concurrency)
+		if err != nil {
+			return err
+		}
 
-			if err := helm.Upgrade(ctx, namespace, oldChrt.Path(), release); err != nil {
-				return err
-			}
+		tmpValuesFile, tmpValuesFileCleanup, err := newTempValuesFileWithOverrides(valuesFile, valuesOverrides)
+		if err != nil {
+			return fmt.Errorf("creating temporary values file: %w", err)
+		}
+		defer tmpValuesFileCleanup()
 
-			return testRelease(ctx, helm, kubectl, release, namespace, releaseSelector, false)
+		targetValuesOverrides, err := mergeValuesOverrides(valuesOverrides, upgradeValuesOverrides)
+		if err != nil {
+			return fmt.Errorf("merging upgrade values: %w", err)
+		}
+		tmpUpgradeValuesFile, tmpUpgradeValuesFileCleanup, err := newTempValuesFileWithOverrides(getUpgradeValuesFile(chrt, valuesFile), targetValuesOverrides)
+		if err != nil {
+			return fmt.Errorf("creating temporary upgrade values file: %w", err)
+		}
+		defer tmpUpgradeValuesFileCleanup()
+
+		namespace, release, releaseSelector, cleanup := generateInstallConfig(cfg, oldChrt, scenarioHelm, kubectl, configRelease)
+		defer func() {
+			leftovers := cleanupRelease(kubectl, namespace, release, err, keepOnFailure, cleanup)
+			resultMutex.Lock()
+			defer resultMutex.Unlock()
+			result.CleanupLeftovers = append(result.CleanupLeftovers, leftovers...)
+		}()
+
+		// Install previous version of chart. If installation fails, ignore this release.
+		if err := scenarioHelm.Install(ctx, namespace, oldChrt.Path(), release, tmpValuesFile); err != nil {
+			return fmt.Errorf("Upgrade testing for release '%s' skipped because of previous revision installation error: %w", release, err)
+		}
+		if _, err := testRelease(ctx, scenarioHelm, kubectl, release, namespace, releaseSelector, true); err != nil {
+			return fmt.Errorf("Upgrade testing for release '%s' skipped because of previous revision testing error", release)
 		}
 
-		if err := fun(); err != nil {
-			result.Error = err
-			break
+		// Upgrade to the version of the chart being verified, with its values on top of those of the previous version.
+		if err := scenarioHelm.Upgrade(ctx, namespace, chrt.Path(), release, tmpUpgradeValuesFile); err != nil {
+			return err
 		}
 
+		testHooks, err := testRelease(ctx, scenarioHelm, kubectl, release, namespace, releaseSelector, false)
+		resultMutex.Lock()
+		defer resultMutex.Unlock()
+		result.TestHooks = append(result.TestHooks, testHooks...)
+		return err
 	}
 
+	result.Scenarios = runScenarios(valuesFiles, concurrency, fun)
+	result.Error = getScenariosError(result.Scenarios)
 	return result
 }
 
+// getScenarioHelm returns the helm client of a scenario: the helm client of the check when the scenarios are run one
+// after the other, else a client of its own, as the clients of the helm actions are not safe for concurrent use.
+func getScenarioHelm(helm *tool.Helm, concurrency int) (*tool.Helm, error) {
+	if concurrency <= 1 {
+		return helm, nil
+	}
+	return helm.Copy()
+}
+
+// getUpgradeValuesFile returns the values file the chart being verified is upgraded with: its own CI values file of the
+// same name as the values file of the previous version, if it has one, else the values file of the previous version.
+func getUpgradeValuesFile(chrt *chart.Chart, valuesFile string) string {
+	if valuesFile != "" && chrt.HasCIValuesFile(valuesFile) {
+		return path.Join(chrt.Path(), "ci", path.Base(valuesFile))
+	}
+	return valuesFile
+}
+
+// mergeValuesOverrides returns a copy of the values overrides with the upgrade values overrides applied on top of them,
+// leaving both unchanged.
+func mergeValuesOverrides(valuesOverrides, upgradeValuesOverrides map[string]interface{}) (map[string]interface{}, error) {
+	merged := make(map[string]interface{})
+	for _, overrides := range []map[string]interface{}{valuesOverrides, upgradeValuesOverrides} {
+		overridesCopy, err := copystructure.Copy(overrides)
+		if err != nil {
+			return nil, err
+		}
+		if overridesCopy, ok := overridesCopy.(map[string]interface{}); ok && len(overridesCopy) > 0 {
+			if err = mergo.MergeWithOverwrite(&merged, overridesCopy); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return merged, nil
+}
+
 // readObjectFromYamlFile unmarshals the given filename and returns an object with its contents.
 func readObjectFromYamlFile(filename string) (map[string]interface{}, error) {
 	// #nosec G304
@@ -361,12 +572,16 @@ func newTempValuesFileWithOverrides(filename string, valuesOverrides map[string]
 
 	if filename != "" {
 		// in the case a filename is provided, read its contents and merge any available values override.
-		obj, err := readObjectFromYamlFile(filename)
+		var err error
+		obj, err = readObjectFromYamlFile(filename)
 		if err != nil {
 			return "", nil, fmt.Errorf("reading values file: %w", err)
 		}
+		if obj == nil {
+			obj = make(map[string]interface{})
+		}
 
-		err = mergo.MergeWithOverwrite(obj, valuesOverrides)
+		err = mergo.MergeWithOverwrite(&obj, valuesOverrides)
 		if err != nil {
 			return "", nil, fmt.Errorf("merging extra values: %w", err)
 		}
@@ -392,7 +607,9 @@ func installAndTestChartRelease(
 	kubectl *tool.Kubectl,
 	valuesOverrides map[string]interface{},
 	configRelease string,
-) chart.TestResult {
+	keepOnFailure bool,
+	concurrency int,
+) releaseTestResult {
 
 	// valuesFiles contains all the configurations that should be
 	// executed; in other words, it performs a test matrix between
@@ -404,48 +621,64 @@ func installAndTestChartRelease(
 		valuesFiles = append(valuesFiles, "")
 	}
 
-	result := chart.TestResult{Chart: chrt}
-
-	for _, valuesFile := range valuesFiles {
+	result := releaseTestResult{TestResult: chart.TestResult{Chart: chrt}}
+	// resultMutex guards the results of the scenarios run concurrently.
+	var resultMutex sync.Mutex
 
-		// Use anonymous function. Otherwise deferred calls would pile up
-		// and be executed in reverse order after the loop.
-		fun := func() error {
-
-			tmpValuesFile, tmpValuesFileCleanup, err := newTempValuesFileWithOverrides(valuesFile, valuesOverrides)
-			if err != nil {
-				// it is required this operation to succeed, otherwise there are no guarantees the values informed using
-				// `--chart-set` are propagated to the installation process, so the process breaks here.
-				return fmt.Errorf("creating temporary values file: %w", err)
-			}
-			defer tmpValuesFileCleanup()
-
-			namespace, release, releaseSelector, releaseCleanup := generateInstallConfig(cfg, chrt, helm, kubectl, configRelease)
-			defer releaseCleanup()
-
-			if err := helm.Install(ctx, namespace, chrt.Path(), release, tmpValuesFile); err != nil {
-				return errors.New(fmt.Sprintf("Chart Install failure: %v", err))
-			}
-			if err = testRelease(ctx, helm, kubectl, release, namespace, releaseSelector, false); err != nil {
-				return errors.New(fmt.Sprintf("Chart test failure: %v", err))
-			}
-			return nil
+	// Use a function per values file. Otherwise deferred calls would pile up
+	// and be executed in reverse order after the loop.
+	fun := func(valuesFile string) (err error) {
+		scenarioHelm, err := getScenarioHelm(helm, concurrency)
+		if err != nil {
+			return err
 		}
 
-		if err := fun(); err != nil {
-			// fail fast approach; could be changed to best effort.
-			result.Error = err
-			break
+		tmpValuesFile, tmpValuesFileCleanup, err := newTempValuesFileWithOverrides(valuesFile, valuesOverrides)
+		if err != nil {
+			// it is required this operation to succeed, otherwise there are no guarantees the values informed using
+			// `--chart-set` are propagated to the installation process, so the process breaks here.
+			return fmt.Errorf("creating temporary values file: %w", err)
+		}
+		defer tmpValuesFileCleanup()
+
+		namespace, release, releaseSelector, releaseCleanup := generateInstallConfig(cfg, chrt, scenarioHelm, kubectl, configRelease)
+		defer func() {
+			leftovers := cleanupRelease(kubectl, namespace, release, err, keepOnFailure, releaseCleanup)
+			resultMutex.Lock()
+			defer resultMutex.Unlock()
+			result.CleanupLeftovers = append(result.CleanupLeftovers, leftovers...)
+		}()
+
+		if err := scenarioHelm.Install(ctx, namespace, chrt.Path(), release, tmpValuesFile); err != nil {
+			return errors.New(fmt.Sprintf("Chart Install failure: %v", err))
 		}
+		testHooks, err := testRelease(ctx, scenarioHelm, kubectl, release, namespace, releaseSelector, false)
+		resultMutex.Lock()
+		result.TestHooks = append(result.TestHooks, testHooks...)
+		resultMutex.Unlock()
+		if err != nil {
+			return errors.New(fmt.Sprintf("Chart test failure: %v", err))
+		}
+		return nil
 	}
 
+	// fail fast approach when the values files are tested one after the other, best effort when they are tested
+	// concurrently.
+	result.Scenarios = runScenarios(valuesFiles, concurrency, fun)
+	result.Error = getScenariosError(result.Scenarios)
 	return result
 }
 
 func setOCVersion(holder AnnotationHolder, envSettings *helmcli.EnvSettings, versioner Versioner) error {
-	// kubectl.GetVersion() returns an error both in case the kubectl command can't be executed and
-	// the value for the OpenShift version key not present.
-	osVersion, getVersionErr := versioner(envSettings)
+	// the versioner returns an error in case the cluster can't be introspected, and the OpenShift version is not found
+	// when the Kubernetes version of a cluster which is not OpenShift is not in the version map.
+	osVersion := ""
+	clusterInfo, getVersionErr := versioner(envSettings)
+	if getVersionErr == nil {
+		utils.LogInfo(fmt.Sprintf("Tested cluster: %s %s, platform %s", clusterInfo.Distribution, clusterInfo.Version, clusterInfo.Platform))
+		holder.SetTestedCluster(clusterInfo.Distribution, clusterInfo.Platform, clusterInfo.Version)
+		osVersion, getVersionErr = getOpenShiftVersion(clusterInfo)
+	}
 
 	// From this point on, an error is set and osVersion is empty.
 	if getVersionErr != nil && holder.GetCertifiedOpenShiftVersionFlag() != "" {
@@ -3,12 +3,17 @@ package checks
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/helm/chart-testing/v3/pkg/chart"
+	"github.com/redhat-certification/chart-verifier/internal/tool"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 	"helm.sh/helm/v3/pkg/cli"
@@ -104,35 +109,78 @@ func TestChartTesting(t *testing.T) {
 	}
 }
 
-func getVersionError(settings *cli.EnvSettings) (string, error) {
-	return "", errors.New("error")
+func TestChartTestingClusterUnavailable(t *testing.T) {
+
+	kubeConfig := filepath.Join(t.TempDir(), "kubeconfig")
+	// nothing listens on port 1 of the loopback interface.
+	require.NoError(t, ioutil.WriteFile(kubeConfig, []byte(strings.Replace(ephemeralKubeConfig, "6443", "1", 1)), 0600))
+	envSettings := cli.New()
+	envSettings.KubeConfig = kubeConfig
+
+	chartUri, err := absPathFromSourceFileLocation("psql-service-0.1.7")
+	require.NoError(t, err)
+
+	r, err := ChartTesting(&CheckOptions{
+		URI:              chartUri,
+		Values:           map[string]interface{}{},
+		ViperConfig:      viper.New(),
+		HelmEnvSettings:  envSettings,
+		AnnotationHolder: &testAnnotationHolder{},
+		Timeout:          time.Minute,
+	})
+	require.NoError(t, err)
+	require.False(t, r.Ok)
+	require.Contains(t, r.Reason, ClusterUnavailable)
+}
+
+func getVersionError(settings *cli.EnvSettings) (*tool.ClusterInfo, error) {
+	return nil, errors.New("error")
 }
 
-func getVersionGood(settings *cli.EnvSettings) (string, error) {
-	return "4.7.9", nil
+func getVersionGood(settings *cli.EnvSettings) (*tool.ClusterInfo, error) {
+	return &tool.ClusterInfo{Distribution: tool.OpenShiftDistribution, Platform: "linux/amd64", Version: "4.7.9", KubeVersion: "1.20"}, nil
+}
+
+func getVersionKubernetes(settings *cli.EnvSettings) (*tool.ClusterInfo, error) {
+	return &tool.ClusterInfo{Distribution: tool.KubernetesDistribution, Platform: "linux/arm64", Version: "v1.24.7", KubeVersion: "1.24"}, nil
+}
+
+func getVersionUnknownKubernetes(settings *cli.EnvSettings) (*tool.ClusterInfo, error) {
+	return &tool.ClusterInfo{Distribution: tool.KubernetesDistribution, Platform: "linux/amd64", Version: "v1.10.0", KubeVersion: "1.10"}, nil
 }
 
 type testAnnotationHolder struct {
 	OpenShiftVersion              string
+	EmulatedOpenShiftVersion      string
 	CertifiedOpenShiftVersionFlag string
+	TestedCluster                 string
 }
 
 func (holder *testAnnotationHolder) SetCertifiedOpenShiftVersion(version string) {
 	holder.OpenShiftVersion = version
 }
 
+func (holder *testAnnotationHolder) SetEmulatedOpenShiftVersion(version string) {
+	holder.EmulatedOpenShiftVersion = version
+}
+
 func (holder *testAnnotationHolder) GetCertifiedOpenShiftVersionFlag() string {
 	return holder.CertifiedOpenShiftVersionFlag
 }
 
 func (holder *testAnnotationHolder) SetSupportedOpenShiftVersions(version string) {}
 
+func (holder *testAnnotationHolder) SetTestedCluster(distribution, platform, version string) {
+	holder.TestedCluster = fmt.Sprintf("%s %s %s", distribution, platform, version)
+}
+
 func TestVersionSetting(t *testing.T) {
 	type testCase struct {
 		description string
 		opts        *CheckOptions
 		versioner   Versioner
 		version     string
+		cluster     string
 		error       string
 	}
 
@@ -142,6 +190,21 @@ func TestVersionSetting(t *testing.T) {
 			opts:        &CheckOptions{AnnotationHolder: &testAnnotationHolder{}},
 			versioner:   getVersionGood,
 			version:     "4.7.9",
+			cluster:     "OpenShift linux/amd64 4.7.9",
+		},
+		{
+			description: "Kubernetes cluster, OpenShift version of its Kubernetes version",
+			opts:        &CheckOptions{AnnotationHolder: &testAnnotationHolder{CertifiedOpenShiftVersionFlag: "4.7.8"}},
+			versioner:   getVersionKubernetes,
+			version:     "4.11",
+			cluster:     "Kubernetes linux/arm64 v1.24.7",
+		},
+		{
+			description: "Kubernetes cluster of unknown Kubernetes version, flag set to 4.7.8",
+			opts:        &CheckOptions{AnnotationHolder: &testAnnotationHolder{CertifiedOpenShiftVersionFlag: "4.7.8"}},
+			versioner:   getVersionUnknownKubernetes,
+			version:     "4.7.8",
+			cluster:     "Kubernetes linux/amd64 v1.10.0",
 		},
 		{
 			description: "oc.Version returns error, flag set to 4.7.8",
@@ -149,6 +212,12 @@ func TestVersionSetting(t *testing.T) {
 			versioner:   getVersionError,
 			version:     "4.7.8",
 		},
+		{
+			description: "Kubernetes cluster of unknown Kubernetes version, flag not set",
+			opts:        &CheckOptions{AnnotationHolder: &testAnnotationHolder{}},
+			versioner:   getVersionUnknownKubernetes,
+			error:       "Missing OpenShift version. internal error: \"1.10\" not found in Kubernetes-OpenShift version map. And the 'openshift-version' flag has not set.",
+		},
 		{
 			description: "oc.Version returns semantic error, flag set to fourseveneight",
 			opts:        &CheckOptions{AnnotationHolder: &testAnnotationHolder{CertifiedOpenShiftVersionFlag: "fourseveneight"}},
@@ -175,6 +244,7 @@ func TestVersionSetting(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 				require.Equal(t, tc.version, tc.opts.AnnotationHolder.(*testAnnotationHolder).OpenShiftVersion)
+				require.Equal(t, tc.cluster, tc.opts.AnnotationHolder.(*testAnnotationHolder).TestedCluster)
 			}
 
 		})
@@ -182,3 +252,59 @@ func TestVersionSetting(t *testing.T) {
 	}
 
 }
+
+func TestNewTempValuesFileWithOverrides(t *testing.T) {
+
+	valuesFile, cleanup, err := newTempValuesFileWithOverrides("chart-0.1.0-v3.ci-values/ci/message-values.yaml", map[string]interface{}{"replicas": 2})
+	require.NoError(t, err)
+	defer cleanup()
+	values, err := readObjectFromYamlFile(valuesFile)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"message": "hello from ci", "replicas": 2}, values)
+}
+
+func TestGetUpgradeValuesFile(t *testing.T) {
+
+	chrt, err := chart.NewChart("chart-0.1.0-v3.ci-values")
+	require.NoError(t, err)
+
+	require.Equal(t, "chart-0.1.0-v3.ci-values/ci/message-values.yaml", getUpgradeValuesFile(chrt, "/previous/ci/message-values.yaml"))
+	require.Equal(t, "/previous/ci/other-values.yaml", getUpgradeValuesFile(chrt, "/previous/ci/other-values.yaml"))
+	require.Equal(t, "", getUpgradeValuesFile(chrt, ""))
+}
+
+func TestGetChartPreviousVersionWithoutSource(t *testing.T) {
+
+	chrt, err := chart.NewChart("chart-0.1.0-v3.ci-values")
+	require.NoError(t, err)
+
+	_, err = getChartPreviousVersion(&CheckOptions{ViperConfig: viper.New(), HelmEnvSettings: cli.New()}, chrt)
+	require.True(t, IsNoPreviousVersion(err))
+	require.Contains(t, err.Error(), UpgradeSourceConfigName+" is not set")
+}
+
+func TestMergeValuesOverrides(t *testing.T) {
+
+	valuesOverrides := map[string]interface{}{"image": map[string]interface{}{"repository": "app", "tag": "1.0"}}
+	upgradeValuesOverrides := map[string]interface{}{"image": map[string]interface{}{"tag": "2.0"}, "migrate": true}
+
+	merged, err := mergeValuesOverrides(valuesOverrides, upgradeValuesOverrides)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"image": map[string]interface{}{"repository": "app", "tag": "2.0"}, "migrate": true}, merged)
+	require.Equal(t, map[string]interface{}{"image": map[string]interface{}{"repository": "app", "tag": "1.0"}}, valuesOverrides)
+
+	merged, err = mergeValuesOverrides(valuesOverrides, nil)
+	require.NoError(t, err)
+	require.Equal(t, valuesOverrides, merged)
+}
+
+func TestAddCleanupLeftovers(t *testing.T) {
+
+	r := addCleanupLeftovers(NewResult(true, ChartTestingSuccess), []string{"Widget/other/chart-widget: still present"})
+	require.True(t, r.Ok)
+	require.Equal(t, ChartTestingSuccess+"\nWarning: cleanup left over : Widget/other/chart-widget: still present", r.Reason)
+
+	r = addCleanupLeftovers(NewResult(false, "Chart test failure"), nil)
+	require.False(t, r.Ok)
+	require.Equal(t, "Chart test failure", r.Reason)
+}
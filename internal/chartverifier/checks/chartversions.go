@@ -0,0 +1,255 @@
+package checks
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/repo"
+
+	"github.com/redhat-certification/chart-verifier/internal/tool"
+)
+
+const (
+	// UpgradeSourceConfigName is the key of the check configuration setting where released versions of the chart are
+	// found: a chart repository url, an OCI registry url or a local directory of chart tarballs.
+	UpgradeSourceConfigName = "upgradeSource"
+	// UpgradeValuesConfigName is the key of the check configuration setting values applied only when upgrading to the
+	// version of the chart being verified, on top of the values the previous version was installed with.
+	UpgradeValuesConfigName = "upgradeValues"
+)
+
+// NoPreviousVersionErr is returned when the source of released versions does not have a version of the chart older
+// than the version being verified.
+type NoPreviousVersionErr string
+
+func (e NoPreviousVersionErr) Error() string {
+	return "no previous version found: " + string(e)
+}
+
+func IsNoPreviousVersion(err error) bool {
+	_, ok := err.(NoPreviousVersionErr)
+	return ok
+}
+
+// chartVersionSource lists the released versions of a chart and fetches one of them.
+type chartVersionSource interface {
+	// Versions returns the released versions of the chart.
+	Versions(name string) ([]string, error)
+	// Fetch returns the uri of the given version of the chart, and a function to clean up after it is used.
+	Fetch(name, version string) (string, func(), error)
+}
+
+func newChartVersionSource(source string, envSettings *cli.EnvSettings) chartVersionSource {
+	switch {
+	case registry.IsOCI(source):
+		return &ociVersionSource{Registry: strings.TrimSuffix(strings.TrimPrefix(source, "oci://"), "/"), EnvSettings: envSettings}
+	case strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://"):
+		return &repoVersionSource{URL: strings.TrimSuffix(source, "/")}
+	default:
+		return &dirVersionSource{Dir: source}
+	}
+}
+
+// getPreviousVersion returns the highest version lower than the current version.
+func getPreviousVersion(versions []string, current string) (string, error) {
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return "", err
+	}
+	var previous *semver.Version
+	for _, version := range versions {
+		v, err := semver.NewVersion(version)
+		if err != nil {
+			continue
+		}
+		if v.LessThan(currentVersion) && (previous == nil || v.GreaterThan(previous)) {
+			previous = v
+		}
+	}
+	if previous == nil {
+		return "", NoPreviousVersionErr(fmt.Sprintf("no version lower than %s", current))
+	}
+	return previous.Original(), nil
+}
+
+// getPreviousChartURI finds the previous version of a chart in the source of released versions and returns its
+// version, the uri to load it from and a function to clean up after it is used.
+func getPreviousChartURI(source string, envSettings *cli.EnvSettings, name, current string) (string, string, func(), error) {
+	versionSource := newChartVersionSource(source, envSettings)
+	versions, err := versionSource.Versions(name)
+	if err != nil {
+		return "", "", nil, err
+	}
+	previous, err := getPreviousVersion(versions, current)
+	if err != nil {
+		return "", "", nil, err
+	}
+	uri, cleanup, err := versionSource.Fetch(name, previous)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return previous, uri, cleanup, nil
+}
+
+// repoVersionSource finds released versions of a chart in the index of a chart repository.
+type repoVersionSource struct {
+	URL   string
+	index *repo.IndexFile
+}
+
+func (s *repoVersionSource) getIndex() (*repo.IndexFile, error) {
+	if s.index != nil {
+		return s.index, nil
+	}
+
+	content, err := tool.ReadURI(s.URL + "/index.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	// the index is loaded from a file so that it is validated and sorted as helm does.
+	indexFile, err := ioutil.TempFile("", "chart-verifier-index-*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(indexFile.Name())
+	_, err = indexFile.Write(content)
+	indexFile.Close()
+	if err != nil {
+		return nil, err
+	}
+	s.index, err = repo.LoadIndexFile(indexFile.Name())
+	return s.index, err
+}
+
+func (s *repoVersionSource) Versions(name string) ([]string, error) {
+	index, err := s.getIndex()
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0)
+	for _, chartVersion := range index.Entries[name] {
+		versions = append(versions, chartVersion.Version)
+	}
+	return versions, nil
+}
+
+func (s *repoVersionSource) Fetch(name, version string) (string, func(), error) {
+	index, err := s.getIndex()
+	if err != nil {
+		return "", nil, err
+	}
+	chartVersion, err := index.Get(name, version)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(chartVersion.URLs) == 0 {
+		return "", nil, fmt.Errorf("chart %s version %s has no url in %s", name, version, s.URL)
+	}
+	uri, err := repo.ResolveReferenceURL(s.URL, chartVersion.URLs[0])
+	return uri, func() {}, err
+}
+
+// ociVersionSource finds released versions of a chart in an OCI registry.
+type ociVersionSource struct {
+	Registry    string
+	EnvSettings *cli.EnvSettings
+}
+
+func (s *ociVersionSource) getClient() (*registry.Client, error) {
+	options := []registry.ClientOption{registry.ClientOptWriter(ioutil.Discard)}
+	if s.EnvSettings != nil {
+		options = append(options, registry.ClientOptCredentialsFile(s.EnvSettings.RegistryConfig))
+	}
+	return registry.NewClient(options...)
+}
+
+func (s *ociVersionSource) Versions(name string) ([]string, error) {
+	client, err := s.getClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.Tags(fmt.Sprintf("%s/%s", s.Registry, name))
+}
+
+func (s *ociVersionSource) Fetch(name, version string) (string, func(), error) {
+	client, err := s.getClient()
+	if err != nil {
+		return "", nil, err
+	}
+	// OCI tags cannot contain "+", helm replaces it with "_".
+	ref := fmt.Sprintf("%s/%s:%s", s.Registry, name, strings.ReplaceAll(version, "+", "_"))
+	result, err := client.Pull(ref, registry.PullOptWithChart(true))
+	if err != nil {
+		return "", nil, err
+	}
+
+	tempDir, err := ioutil.TempDir("", "chart-verifier-previous-*")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(tempDir) }
+	uri := path.Join(tempDir, fmt.Sprintf("%s-%s.tgz", name, version))
+	if err = ioutil.WriteFile(uri, result.Chart.Data, 0644); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return uri, cleanup, nil
+}
+
+// dirVersionSource finds released versions of a chart in a local directory of chart tarballs.
+type dirVersionSource struct {
+	Dir string
+}
+
+// getTarballs returns the path of each tarball of the chart in the directory by version.
+func (s *dirVersionSource) getTarballs(name string) (map[string]string, error) {
+	tarballs, err := filepath.Glob(filepath.Join(s.Dir, "*.tgz"))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(s.Dir); err != nil {
+		return nil, err
+	}
+	versions := make(map[string]string)
+	for _, tarball := range tarballs {
+		c, err := loader.Load(tarball)
+		if err != nil || c.Metadata == nil || c.Metadata.Name != name {
+			continue
+		}
+		versions[c.Metadata.Version] = tarball
+	}
+	return versions, nil
+}
+
+func (s *dirVersionSource) Versions(name string) ([]string, error) {
+	tarballs, err := s.getTarballs(name)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(tarballs))
+	for version := range tarballs {
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+func (s *dirVersionSource) Fetch(name, version string) (string, func(), error) {
+	tarballs, err := s.getTarballs(name)
+	if err != nil {
+		return "", nil, err
+	}
+	tarball, ok := tarballs[version]
+	if !ok {
+		return "", nil, ChartNotFoundErr(fmt.Sprintf("%s version %s in %s", name, version, s.Dir))
+	}
+	return tarball, func() {}, nil
+}
@@ -0,0 +1,96 @@
+package checks
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// saveChartVersions saves a tarball of the chart in the directory for each version.
+func saveChartVersions(t *testing.T, dir string, name string, versions ...string) {
+	for _, version := range versions {
+		c := &chart.Chart{Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: name, Version: version}}
+		_, err := chartutil.Save(c, dir)
+		require.NoError(t, err)
+	}
+}
+
+func TestGetPreviousVersion(t *testing.T) {
+
+	previous, err := getPreviousVersion([]string{"0.1.0", "0.3.0", "0.2.1", "0.2.0", "not-a-version"}, "0.3.0")
+	require.NoError(t, err)
+	require.Equal(t, "0.2.1", previous)
+
+	previous, err = getPreviousVersion([]string{"1.0.0-rc.1", "0.9.0"}, "1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0-rc.1", previous)
+
+	_, err = getPreviousVersion([]string{"0.2.0"}, "0.1.0")
+	require.True(t, IsNoPreviousVersion(err))
+
+	_, err = getPreviousVersion([]string{"0.1.0"}, "latest")
+	require.Error(t, err)
+}
+
+func TestDirVersionSource(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "chart-verifier-versions-*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	saveChartVersions(t, dir, "chart", "0.1.0", "0.2.0", "0.3.0")
+	saveChartVersions(t, dir, "other", "0.2.5")
+
+	version, uri, cleanup, err := getPreviousChartURI(dir, nil, "chart", "0.3.0")
+	require.NoError(t, err)
+	defer cleanup()
+	require.Equal(t, "0.2.0", version)
+	require.Equal(t, filepath.Join(dir, "chart-0.2.0.tgz"), uri)
+
+	_, _, _, err = getPreviousChartURI(dir, nil, "chart", "0.1.0")
+	require.True(t, IsNoPreviousVersion(err))
+
+	_, _, _, err = getPreviousChartURI(filepath.Join(dir, "missing"), nil, "chart", "0.3.0")
+	require.Error(t, err)
+}
+
+func TestRepoVersionSource(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "chart-verifier-versions-*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	saveChartVersions(t, dir, "chart", "0.1.0", "0.2.0")
+
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer server.Close()
+
+	index, err := repo.IndexDirectory(dir, server.URL+"/charts")
+	require.NoError(t, err)
+	require.NoError(t, index.WriteFile(filepath.Join(dir, "index.yaml"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "charts"), 0755))
+	require.NoError(t, os.Rename(filepath.Join(dir, "chart-0.1.0.tgz"), filepath.Join(dir, "charts", "chart-0.1.0.tgz")))
+
+	version, uri, cleanup, err := getPreviousChartURI(server.URL, nil, "chart", "0.2.0")
+	require.NoError(t, err)
+	defer cleanup()
+	require.Equal(t, "0.1.0", version)
+	require.Equal(t, fmt.Sprintf("%s/charts/chart-0.1.0.tgz", server.URL), uri)
+
+	previousUrl, err := url.Parse(uri)
+	require.NoError(t, err)
+	previous, err := loadChartFromRemote(previousUrl)
+	require.NoError(t, err)
+	require.Equal(t, "0.1.0", previous.Metadata.Version)
+
+	_, _, _, err = getPreviousChartURI(server.URL+"/missing", nil, "chart", "0.2.0")
+	require.Error(t, err)
+}
@@ -25,15 +25,13 @@ import (
 	"path"
 	"strings"
 
-	"github.com/Masterminds/sprig"
 	"github.com/pkg/errors"
-	"golang.org/x/mod/semver"
 	"helm.sh/helm/v3/pkg/action"
-	"helm.sh/helm/v3/pkg/lint"
-	"helm.sh/helm/v3/pkg/lint/support"
 
 	"github.com/redhat-certification/chart-verifier/internal/chartverifier/pyxis"
 	"github.com/redhat-certification/chart-verifier/internal/tool"
+	apiChecks "github.com/redhat-certification/chart-verifier/pkg/chartverifier/checks"
+	"github.com/redhat-certification/chart-verifier/pkg/chartverifier/ocprange"
 )
 
 const (
@@ -63,6 +61,13 @@ const (
 	ImageCertified               = "Image is Red Hat certified"
 	ImageNotCertified            = "Image is not Red Hat certified"
 	ChartTestingSuccess          = "Chart tests have passed"
+	ClusterUnavailable           = "Cluster unavailable"
+	ChartUpgradeTested           = "Chart upgrade tested"
+	ChartUpgradeNotTested        = "Chart upgrade not tested"
+	ChartUpgradeFailed           = "Chart upgrade failed"
+	ChartTestHook                = "Test hook"
+	ChartTestScenario            = "Values scenario"
+	ChartCleanupLeftover         = "Warning: cleanup left over"
 	MetadataFailure              = "Empty metadata in chart"
 	RequiredAnnotationsSuccess   = "All required annotations present"
 	RequiredAnnotationsFailure   = "Missing required annotations"
@@ -74,9 +79,39 @@ const (
 	SignatureNoKey               = "Signature verification skipped, a public key was not specified"
 	ImageCertifySkipped          = "Image certification skipped"
 	RedHatRegistry               = "registry.redhat.io/"
+	DefaultValuesScenario        = "default"
+	CIValuesDir                  = "ci"
+	CIValuesFileSuffix           = "-values.yaml"
+	RestrictedSCCSuccess         = "Chart can be installed with the restricted-v2 SCC"
+	RestrictedSCCFailure         = "Chart cannot be installed with the restricted-v2 SCC"
+	RestrictedSCCRenderFailure   = "Failed to check security context, error running helm template"
+	LeastPrivilegeRBACSuccess    = "Chart RBAC follows least privilege"
+	LeastPrivilegeRBACFailure    = "Chart RBAC does not follow least privilege"
+	LeastPrivilegeRBACRenderFail = "Failed to check RBAC, error running helm template"
+	ServiceAccountPermissions    = "Service account permissions"
+	ClusterScopedObject          = "Cluster scoped object"
+	KeywordsAreCategories        = "Chart keywords include OpenShift categories"
+	KeywordsAreNotCategories     = "Chart keywords do not include an OpenShift category"
+	ChartIsCommercial            = "Chart is a commercial chart"
+	ChartIsNotCommercial         = "Chart is not a commercial chart"
+	ChartIsCommunity             = "Chart is a community chart"
+	ChartIsNotCommunity          = "Chart is not a community chart"
+	ResourceRequirementsSuccess  = "Containers have resource requests, limits and probes"
+	ResourceRequirementsFailure  = "Container resource requirements not met"
+	ResourcesRenderFailure       = "Failed to check resource requirements, error running helm template"
+	RenderFailure                = "Failed to check rendered objects, error running helm template"
+	DependenciesValid            = "Chart dependencies are valid"
+	DependenciesNotValid         = "Chart dependencies are not valid"
+	SubchartMandatoryCheckFailed = "Mandatory check failed for subchart"
+	SubchartOptionalCheckFailed  = "Optional check failed for subchart"
+	MetadataComplete             = "Chart metadata is complete"
+	MetadataIncomplete           = "Chart metadata is incomplete"
+	ReadmeComplete               = "Chart README is complete"
+	ReadmeIncomplete             = "Chart README is incomplete"
 )
 
 var (
+	// requiredAnnotations are the annotations required when the check configuration does not list them.
 	requiredAnnotations = [...]string{"charts.openshift.io/name"}
 )
 
@@ -114,6 +149,49 @@ func HasReadme(opts *CheckOptions) (Result, error) {
 	return r, nil
 }
 
+func HasCompleteReadme(opts *CheckOptions) (Result, error) {
+	c, _, err := LoadChartFromURI(opts)
+	if err != nil {
+		return Result{}, err
+	}
+
+	content, found := getReadme(c)
+	if !found {
+		return NewResult(false, ReadmeDoesNotExist), nil
+	}
+	if len(strings.TrimSpace(content)) == 0 {
+		return NewResult(false, fmt.Sprintf("%s : README is empty", ReadmeIncomplete)), nil
+	}
+
+	issues := make([]string, 0)
+	document := parseReadme(content)
+	for _, section := range getMissingSections(document.Headings, getReadmeSections(opts)) {
+		issues = append(issues, fmt.Sprintf("missing %s section", section))
+	}
+	if getRequireParameters(opts) && len(c.Values) > 0 {
+		if len(document.Parameters) == 0 {
+			issues = append(issues, "no parameters table documents the values")
+		} else {
+			undocumented, stale := getParameterIssues(c.Values, document.Parameters)
+			for _, parameter := range undocumented {
+				issues = append(issues, fmt.Sprintf("parameter %s is not documented", parameter))
+			}
+			for _, parameter := range stale {
+				issues = append(issues, fmt.Sprintf("parameter %s is documented but not in values.yaml", parameter))
+			}
+		}
+	}
+
+	if len(issues) == 0 {
+		return NewResult(true, ReadmeComplete), nil
+	}
+	r := NewResult(false, "")
+	for _, issue := range issues {
+		r.AddResult(false, fmt.Sprintf("%s : %s", ReadmeIncomplete, issue))
+	}
+	return r, nil
+}
+
 func ContainsTest(opts *CheckOptions) (Result, error) {
 	c, _, err := LoadChartFromURI(opts)
 	if err != nil {
@@ -164,15 +242,45 @@ func ContainsValuesSchema(opts *CheckOptions) (Result, error) {
 }
 
 func KeywordsAreOpenshiftCategories(opts *CheckOptions) (Result, error) {
-	return notImplemented()
+	c, _, err := LoadChartFromURI(opts)
+	if err != nil {
+		return NewResult(false, err.Error()), err
+	}
+
+	categoryKeywords := getOpenShiftCategoryKeywords(c.Metadata.Keywords, getOpenShiftCategories(opts))
+	if len(categoryKeywords) == 0 {
+		return NewResult(false, fmt.Sprintf("%s: %v", KeywordsAreNotCategories, c.Metadata.Keywords)), nil
+	}
+	return NewResult(true, fmt.Sprintf("%s: %v", KeywordsAreCategories, categoryKeywords)), nil
 }
 
 func IsCommercialChart(opts *CheckOptions) (Result, error) {
-	return notImplemented()
+	c, _, err := LoadChartFromURI(opts)
+	if err != nil {
+		return NewResult(false, err.Error()), err
+	}
+
+	providerType, basis := getProviderType(c)
+	if providerType != PartnerProviderType && providerType != RedHatProviderType {
+		return NewResult(false, fmt.Sprintf("%s : %s", ChartIsNotCommercial, basis)), nil
+	}
+	if provider := c.Metadata.Annotations[ProviderAnnotation]; len(provider) == 0 {
+		return NewResult(false, fmt.Sprintf("%s : annotation %s is not set", ChartIsNotCommercial, ProviderAnnotation)), nil
+	}
+	return NewResult(true, fmt.Sprintf("%s : %s", ChartIsCommercial, basis)), nil
 }
 
 func IsCommunityChart(opts *CheckOptions) (Result, error) {
-	return notImplemented()
+	c, _, err := LoadChartFromURI(opts)
+	if err != nil {
+		return NewResult(false, err.Error()), err
+	}
+
+	providerType, basis := getProviderType(c)
+	if providerType != CommunityProviderType {
+		return NewResult(false, fmt.Sprintf("%s : %s", ChartIsNotCommunity, basis)), nil
+	}
+	return NewResult(true, fmt.Sprintf("%s : %s", ChartIsCommunity, basis)), nil
 }
 
 func HasKubeVersion(opts *CheckOptions) (Result, error) {
@@ -211,19 +319,7 @@ func HasKubeVersion_V1_1(opts *CheckOptions) (Result, error) {
 }
 
 func NotContainCRDs(opts *CheckOptions) (Result, error) {
-	c, _, err := LoadChartFromURI(opts)
-	if err != nil {
-		return NewResult(false, err.Error()), err
-	}
-
-	r := NewResult(true, ChartDoesNotContainCRDs)
-
-	if len(c.CRDObjects()) > 0 {
-		r.Ok = false
-		r.SetResult(false, ChartContainCRDs)
-	}
-
-	return r, nil
+	return notContainKinds(opts, ChartDoesNotContainCRDs, ChartContainCRDs, "CustomResourceDefinition")
 }
 
 func HelmLint(opts *CheckOptions) (Result, error) {
@@ -231,13 +327,26 @@ func HelmLint(opts *CheckOptions) (Result, error) {
 	if err != nil {
 		return NewResult(false, err.Error()), err
 	}
+	options, err := getHelmLintOptions(opts)
+	if err != nil {
+		return NewResult(false, err.Error()), err
+	}
+	scenarios, err := getValuesScenarios(opts)
+	if err != nil {
+		return NewResult(false, err.Error()), err
+	}
 	r := NewResult(true, HelmLintSuccessful)
-	linter := lint.All(p, opts.Values, "default", false)
-	if linter.HighestSeverity > support.WarningSev {
-		reason := ""
-		for _, m := range linter.Messages {
-			reason = reason + m.Error() + "\n"
+	reason := ""
+	for _, scenario := range scenarios {
+		findings := getLintFindings(p, scenario.Values, options)
+		if len(findings) > 0 {
+			r.Ok = false
+			for _, finding := range findings {
+				reason = reason + scenarioReason(scenarios, scenario, finding.String()) + "\n"
+			}
 		}
+	}
+	if !r.Ok {
 		r.SetResult(false, fmt.Sprintf("%s %s", HelmLintHasFailedPrefix, reason))
 	}
 	return r, nil
@@ -248,24 +357,36 @@ func NotContainsInfraPluginsAndDrivers(opts *CheckOptions) (Result, error) {
 }
 
 func NotContainCSIObjects(opts *CheckOptions) (Result, error) {
-	c, _, err := LoadChartFromURI(opts)
+	return notContainKinds(opts, CSIObjectsDoesNotExist, CSIObjectsExist, "CSIDriver")
+}
+
+// notContainKinds renders the chart for each values scenario and fails for each object found of the given kinds, or if
+// the chart cannot be rendered.
+func notContainKinds(opts *CheckOptions, successReason string, failureReason string, kinds ...string) (Result, error) {
+	scenarios, err := getValuesScenarios(opts)
 	if err != nil {
-		return Result{}, err
+		return NewResult(false, err.Error()), err
 	}
-	r := NewResult(true, CSIObjectsDoesNotExist)
-	for _, f := range c.Templates {
-		if !strings.HasSuffix(f.Name, ".yaml") {
+
+	r := NewResult(true, "")
+	for _, scenario := range scenarios {
+		objects, err := getRenderedObjects(opts, scenario.Values, "")
+		if err != nil {
+			r.AddResult(false, scenarioReason(scenarios, scenario, fmt.Sprintf("%s : %v", RenderFailure, err)))
 			continue
 		}
-		for _, v := range strings.Split(string(f.Data), "\n") {
-			if strings.HasPrefix(v, "kind") {
-				if strings.TrimSpace(strings.Split(v, ":")[1]) == "CSIDriver" {
-					r.SetResult(false, CSIObjectsExist)
+		for _, object := range objects {
+			for _, kind := range kinds {
+				if object.Kind() == kind {
+					r.AddResult(false, scenarioReason(scenarios, scenario, fmt.Sprintf("%s : %s", failureReason, object)))
 				}
 			}
 		}
 	}
 
+	if r.Ok {
+		r.SetResult(true, successReason)
+	}
 	return r, nil
 }
 
@@ -274,7 +395,177 @@ func CanBeInstalledWithoutManualPreRequisites(opts *CheckOptions) (Result, error
 }
 
 func CanBeInstalledWithoutClusterAdminPrivileges(opts *CheckOptions) (Result, error) {
-	return notImplemented()
+	scenarios, err := getValuesScenarios(opts)
+	if err != nil {
+		return NewResult(false, err.Error()), err
+	}
+
+	r := NewResult(true, "")
+	for _, scenario := range scenarios {
+		objects, err := getRenderedObjects(opts, scenario.Values, "")
+		if err != nil {
+			r.AddResult(false, scenarioReason(scenarios, scenario, fmt.Sprintf("%s : %v", RestrictedSCCRenderFailure, err)))
+			continue
+		}
+		reports, err := getSecurityReports(objects)
+		if err != nil {
+			r.AddResult(false, scenarioReason(scenarios, scenario, fmt.Sprintf("%s : %v", RestrictedSCCFailure, err)))
+			continue
+		}
+		for _, report := range reports {
+			r.AddResult(false, scenarioReason(scenarios, scenario, fmt.Sprintf("%s : %s requires the %s SCC : %s",
+				RestrictedSCCFailure, report.Object, report.RequiredSCC(), strings.Join(report.Details, ", "))))
+		}
+	}
+
+	if r.Ok {
+		r.SetResult(true, RestrictedSCCSuccess)
+	}
+	return r, nil
+}
+
+func HasLeastPrivilegeRBAC(opts *CheckOptions) (Result, error) {
+	scenarios, err := getValuesScenarios(opts)
+	if err != nil {
+		return NewResult(false, err.Error()), err
+	}
+
+	var failures, details []string
+	for _, scenario := range scenarios {
+		objects, err := getRenderedObjects(opts, scenario.Values, "")
+		if err != nil {
+			failures = append(failures, scenarioReason(scenarios, scenario, fmt.Sprintf("%s : %v", LeastPrivilegeRBACRenderFail, err)))
+			continue
+		}
+		report, err := getRBACReport(objects)
+		if err != nil {
+			failures = append(failures, scenarioReason(scenarios, scenario, fmt.Sprintf("%s : %v", LeastPrivilegeRBACFailure, err)))
+			continue
+		}
+		for _, finding := range report.Findings {
+			failures = append(failures, scenarioReason(scenarios, scenario, fmt.Sprintf("%s : %s", LeastPrivilegeRBACFailure, finding)))
+		}
+		for _, permissions := range report.Permissions {
+			details = append(details, scenarioReason(scenarios, scenario, fmt.Sprintf("%s : %s", ServiceAccountPermissions, permissions)))
+		}
+		for _, object := range report.ClusterScopedObjects {
+			details = append(details, scenarioReason(scenarios, scenario, fmt.Sprintf("%s : %s", ClusterScopedObject, object)))
+		}
+	}
+
+	r := NewResult(true, LeastPrivilegeRBACSuccess)
+	if len(failures) > 0 {
+		r = NewResult(false, "")
+		for _, failure := range failures {
+			r.AddResult(false, failure)
+		}
+	}
+	for _, detail := range details {
+		r.AddResult(true, detail)
+	}
+	return r, nil
+}
+
+func HasResourceRequirements(opts *CheckOptions) (Result, error) {
+	scenarios, err := getValuesScenarios(opts)
+	if err != nil {
+		return NewResult(false, err.Error()), err
+	}
+	thresholds := getResourceThresholds(opts)
+
+	r := NewResult(true, "")
+	for _, scenario := range scenarios {
+		objects, err := getRenderedObjects(opts, scenario.Values, "")
+		if err != nil {
+			r.AddResult(false, scenarioReason(scenarios, scenario, fmt.Sprintf("%s : %v", ResourcesRenderFailure, err)))
+			continue
+		}
+		reports, err := getResourceReports(objects, thresholds)
+		if err != nil {
+			r.AddResult(false, scenarioReason(scenarios, scenario, fmt.Sprintf("%s : %v", ResourceRequirementsFailure, err)))
+			continue
+		}
+		for _, report := range reports {
+			r.AddResult(false, scenarioReason(scenarios, scenario, fmt.Sprintf("%s : %s : container %s : %s",
+				ResourceRequirementsFailure, report.Object, report.Container, strings.Join(report.Issues, ", "))))
+		}
+	}
+
+	if r.Ok {
+		r.SetResult(true, ResourceRequirementsSuccess)
+	}
+	return r, nil
+}
+
+func DependenciesAreValid(opts *CheckOptions) (Result, error) {
+	c, _, err := LoadChartFromURI(opts)
+	if err != nil {
+		return NewResult(false, err.Error()), err
+	}
+
+	r := NewResult(true, DependenciesValid)
+	issues := getDependencyIssues(c, getChartDir(opts.URI))
+	if len(issues) > 0 {
+		r = NewResult(false, "")
+		for _, issue := range issues {
+			r.AddResult(false, fmt.Sprintf("%s : %s", DependenciesNotValid, issue))
+		}
+	}
+
+	if opts.ViperConfig != nil && opts.ViperConfig.GetBool(RecursiveConfigName) {
+		subchartResults, err := runSubchartChecks(c, opts)
+		if err != nil {
+			return NewResult(false, err.Error()), err
+		}
+		// only a mandatory check failing for a subchart fails the check, optional ones are only reported.
+		mandatoryFailed := make([]string, 0)
+		optionalFailed := make([]string, 0)
+		for _, sub := range subchartResults {
+			if sub.Result.Ok {
+				continue
+			}
+			failure := fmt.Sprintf("%s : %s/%s", sub.Subchart, sub.Check.CheckId.Version, sub.Check.CheckId.Name)
+			if sub.Check.Type == apiChecks.MandatoryCheckType {
+				mandatoryFailed = append(mandatoryFailed, failure)
+			} else {
+				optionalFailed = append(optionalFailed, failure)
+			}
+		}
+		if len(mandatoryFailed) > 0 && r.Ok {
+			r.SetResult(false, DependenciesNotValid)
+		}
+		for _, failure := range mandatoryFailed {
+			r.AddResult(false, fmt.Sprintf("%s : %s", SubchartMandatoryCheckFailed, failure))
+		}
+		for _, failure := range optionalFailed {
+			r.AddResult(true, fmt.Sprintf("%s : %s", SubchartOptionalCheckFailed, failure))
+		}
+		r.Subcharts = subchartResults
+	}
+
+	return r, nil
+}
+
+func HasCompleteMetadata(opts *CheckOptions) (Result, error) {
+	c, _, err := LoadChartFromURI(opts)
+	if err != nil {
+		return NewResult(false, err.Error()), err
+	}
+
+	if c.Metadata == nil {
+		return NewResult(false, MetadataFailure), nil
+	}
+
+	issues := getMetadataIssues(c.Metadata, getRequiredMetadata(opts), getLicenseAnnotation(opts))
+	if len(issues) == 0 {
+		return NewResult(true, MetadataComplete), nil
+	}
+
+	r := NewResult(false, "")
+	for _, issue := range issues {
+		r.AddResult(false, fmt.Sprintf("%s : %s", MetadataIncomplete, issue))
+	}
+	return r, nil
 }
 
 func ImagesAreCertified(opts *CheckOptions) (Result, error) {
@@ -306,7 +597,7 @@ func RequiredAnnotationsPresent(opts *CheckOptions) (Result, error) {
 	}
 
 	missingAnnotations := make([]string, 0)
-	for _, annotation := range requiredAnnotations {
+	for _, annotation := range getRequiredAnnotations(opts) {
 		if _, ok := c.Metadata.Annotations[annotation]; !ok {
 			missingAnnotations = append(missingAnnotations, annotation)
 		}
@@ -439,40 +730,11 @@ func parseImageReference(image string) pyxis.ImageReference {
 
 func getOCPRange(kubeVersionRange string) (string, error) {
 
-	semverCompare := sprig.GenericFuncMap()["semverCompare"].(func(string, string) (bool, error))
-	minOCPVersion := ""
-	maxOCPVersion := ""
-	for kubeVersion, OCPVersion := range tool.GetKubeOpenShiftVersionMap() {
-		match, err := semverCompare(kubeVersionRange, kubeVersion)
-		if err != nil {
-			return "", fmt.Errorf("%s : %s", KuberVersionProcessingError, err)
-		}
-		if match {
-			testOCPVersion := fmt.Sprintf("v%s", OCPVersion)
-			if minOCPVersion == "" || semver.Compare(testOCPVersion, fmt.Sprintf("v%s", minOCPVersion)) < 0 {
-				minOCPVersion = OCPVersion
-			}
-			if maxOCPVersion == "" || semver.Compare(testOCPVersion, fmt.Sprintf("v%s", maxOCPVersion)) > 0 {
-				maxOCPVersion = OCPVersion
-			}
-		}
-	}
-	// Check if min ocp range is open ended, for example 1.* or >-=1.20
-	// To do this see if 1.999 is valid for the min OCP version range, not perfect but works until kubernetes hits 2.0.
-	if minOCPVersion != "" {
-		match, _ := semverCompare(kubeVersionRange, "1.999")
-		if match {
-			return fmt.Sprintf(">=%s", minOCPVersion), nil
-		} else {
-			if minOCPVersion == maxOCPVersion {
-				return minOCPVersion, nil
-			} else {
-				return fmt.Sprintf("%s - %s", minOCPVersion, maxOCPVersion), nil
-			}
-		}
+	ranges, err := ocprange.GetRanges(kubeVersionRange, tool.GetKubeOpenShiftVersionMap())
+	if err != nil {
+		return "", fmt.Errorf("%s : %s", KuberVersionProcessingError, err)
 	}
-
-	return "", fmt.Errorf("%s : Failed to determine a minimum OCP version", KuberVersionProcessingError)
+	return ranges.String(), nil
 
 }
 
@@ -524,41 +786,35 @@ func certifyImages(r Result, opts *CheckOptions, registry string) Result {
 		}
 	}
 
-	images, err := getImageReferences(opts.URI, opts.Values, kubeVersion)
-
+	scenarios, err := getValuesScenarios(opts)
 	if err != nil {
-		r.SetResult(false, fmt.Sprintf("%s : Failed to get images, error running helm template : %v", ImageCertifyFailed, err))
-	} else if len(images) == 0 {
-		r.SetResult(true, NoImagesToCertify)
-	} else {
-		for _, image := range images {
+		r.SetResult(false, fmt.Sprintf("%s : %v", ImageCertifyFailed, err))
+		return r
+	}
 
-			err = nil
-			imageRef := parseImageReference(image)
+	// images are commonly shared between scenarios, so each image is only certified once.
+	certifiedImages := make(map[string]imageCertification)
 
-			if len(imageRef.Registries) == 0 {
-				imageRef.Registries, err = pyxis.GetImageRegistries(imageRef.Repository)
-			}
+	for _, scenario := range scenarios {
 
-			if err != nil {
-				r.AddResult(false, fmt.Sprintf("%s : %s : %v", ImageNotCertified, image, err))
-			} else if len(imageRef.Registries) == 0 {
-				r.AddResult(false, fmt.Sprintf("%s : %s", ImageNotCertified, image))
-			} else {
-				certified, checkImageErr := pyxis.IsImageInRegistry(imageRef)
-				if !certified {
-
-					if strings.Contains(checkImageErr.Error(), "No images found for Registry/Repository") && registry != "" {
-						if strings.HasPrefix(image, registry) {
-							r.SetSkipped(fmt.Sprintf("%s : %s", ImageCertifySkipped, image))
-						} else {
-							r.AddResult(false, fmt.Sprintf("%s : %s", ImageNotCertified, image))
-						}
-					} else {
-						r.AddResult(false, fmt.Sprintf("%s : %s : %v", ImageCertifyFailed, image, checkImageErr))
-					}
+		images, err := getImageReferences(opts.URI, scenario.Values, kubeVersion)
+
+		if err != nil {
+			r.AddResult(false, scenarioReason(scenarios, scenario, fmt.Sprintf("%s : Failed to get images, error running helm template : %v", ImageCertifyFailed, err)))
+		} else if len(images) == 0 {
+			r.AddResult(true, scenarioReason(scenarios, scenario, NoImagesToCertify))
+		} else {
+			for _, image := range images {
+				certification, ok := certifiedImages[image]
+				if !ok {
+					certification = certifyImage(image, registry)
+					certifiedImages[image] = certification
+				}
+				reason := scenarioReason(scenarios, scenario, certification.reason)
+				if certification.skipped {
+					r.SetSkipped(reason)
 				} else {
-					r.AddResult(true, fmt.Sprintf("%s : %s", ImageCertified, image))
+					r.AddResult(certification.ok, reason)
 				}
 			}
 		}
@@ -566,3 +822,50 @@ func certifyImages(r Result, opts *CheckOptions, registry string) Result {
 
 	return r
 }
+
+// imageCertification holds the outcome of certifying a single image.
+type imageCertification struct {
+	ok      bool
+	skipped bool
+	reason  string
+}
+
+func certifyImage(image string, registry string) imageCertification {
+
+	var err error
+	imageRef := parseImageReference(image)
+
+	if len(imageRef.Registries) == 0 {
+		imageRef.Registries, err = pyxis.GetImageRegistries(imageRef.Repository)
+	}
+
+	if err != nil {
+		return imageCertification{ok: false, reason: fmt.Sprintf("%s : %s : %v", ImageNotCertified, image, err)}
+	} else if len(imageRef.Registries) == 0 {
+		return imageCertification{ok: false, reason: fmt.Sprintf("%s : %s", ImageNotCertified, image)}
+	}
+
+	certified, checkImageErr := pyxis.IsImageInRegistry(imageRef)
+	if !certified {
+		if strings.Contains(checkImageErr.Error(), "No images found for Registry/Repository") && registry != "" {
+			if strings.HasPrefix(image, registry) {
+				return imageCertification{ok: true, skipped: true, reason: fmt.Sprintf("%s : %s", ImageCertifySkipped, image)}
+			}
+			return imageCertification{ok: false, reason: fmt.Sprintf("%s : %s", ImageNotCertified, image)}
+		}
+		return imageCertification{ok: false, reason: fmt.Sprintf("%s : %s : %v", ImageCertifyFailed, image, checkImageErr)}
+	}
+
+	return imageCertification{ok: true, reason: fmt.Sprintf("%s : %s", ImageCertified, image)}
+}
+
+// contains returns true if a list of strings, such as the verbs of a policy rule or the words of a heading, holds a
+// value.
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
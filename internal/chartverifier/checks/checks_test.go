@@ -23,9 +23,12 @@ import (
 
 	"github.com/redhat-certification/chart-verifier/internal/chartverifier/pyxis"
 	"github.com/redhat-certification/chart-verifier/internal/tool"
+	apiChecks "github.com/redhat-certification/chart-verifier/pkg/chartverifier/checks"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/lint/support"
 )
 
 func TestIsHelmV3(t *testing.T) {
@@ -104,6 +107,40 @@ func TestHasReadme(t *testing.T) {
 	}
 }
 
+func TestHasCompleteReadme(t *testing.T) {
+
+	t.Run("Chart with complete README", func(t *testing.T) {
+		r, err := HasCompleteReadme(&CheckOptions{URI: "chart-0.1.0-v3.readme", ViperConfig: viper.New(), HelmEnvSettings: cli.New()})
+		require.NoError(t, err)
+		require.True(t, r.Ok)
+		require.Equal(t, ReadmeComplete, r.Reason)
+	})
+
+	t.Run("Chart with incomplete README", func(t *testing.T) {
+		r, err := HasCompleteReadme(&CheckOptions{URI: "chart-0.1.0-v3.valid.tgz", ViperConfig: viper.New(), HelmEnvSettings: cli.New()})
+		require.NoError(t, err)
+		require.False(t, r.Ok)
+		require.Contains(t, r.Reason, fmt.Sprintf("%s : missing uninstall section", ReadmeIncomplete))
+	})
+
+	t.Run("Chart with README sections and parameters from config", func(t *testing.T) {
+		config := viper.New()
+		config.Set(ReadmeSectionsConfigName, []string{"upgrading|upgrade"})
+		config.Set(RequireParametersConfigName, false)
+		r, err := HasCompleteReadme(&CheckOptions{URI: "chart-0.1.0-v3.readme", ViperConfig: config, HelmEnvSettings: cli.New()})
+		require.NoError(t, err)
+		require.False(t, r.Ok)
+		require.Equal(t, fmt.Sprintf("%s : missing upgrading section", ReadmeIncomplete), r.Reason)
+	})
+
+	t.Run("Chart without README", func(t *testing.T) {
+		r, err := HasCompleteReadme(&CheckOptions{URI: "chart-0.1.0-v3.without-readme.tgz", ViperConfig: viper.New(), HelmEnvSettings: cli.New()})
+		require.NoError(t, err)
+		require.False(t, r.Ok)
+		require.Equal(t, ReadmeDoesNotExist, r.Reason)
+	})
+}
+
 func TestContainsTest(t *testing.T) {
 	type testCase struct {
 		description string
@@ -257,6 +294,7 @@ func TestNotContainCRDs(t *testing.T) {
 	type testCase struct {
 		description string
 		uri         string
+		object      string
 	}
 
 	positiveTestCases := []testCase{
@@ -275,7 +313,7 @@ func TestNotContainCRDs(t *testing.T) {
 	}
 
 	negativeTestCases := []testCase{
-		{description: "Contain CRDs", uri: "chart-0.1.0-v3.with-crd.tgz"},
+		{description: "Contain CRDs", uri: "chart-0.1.0-v3.with-crd.tgz", object: "CustomResourceDefinition/backservs.service.example.com (crds/backend.yaml)"},
 	}
 
 	for _, tc := range negativeTestCases {
@@ -285,7 +323,7 @@ func TestNotContainCRDs(t *testing.T) {
 			require.NoError(t, err)
 			require.NotNil(t, r)
 			require.False(t, r.Ok)
-			require.Equal(t, ChartContainCRDs, r.Reason)
+			require.Equal(t, fmt.Sprintf("%s : %s", ChartContainCRDs, tc.object), r.Reason)
 		})
 	}
 }
@@ -294,6 +332,7 @@ func TestNotContainCSIObjects(t *testing.T) {
 	type testCase struct {
 		description string
 		uri         string
+		object      string
 	}
 
 	positiveTestCases := []testCase{
@@ -312,7 +351,7 @@ func TestNotContainCSIObjects(t *testing.T) {
 	}
 
 	negativeTestCases := []testCase{
-		{description: "Contain CRDs", uri: "chart-0.1.0-v3.with-csi.tgz"},
+		{description: "Contain CSI objects", uri: "chart-0.1.0-v3.with-csi.tgz", object: "CSIDriver/mycsidriver.example.com (templates/csidriver.yaml)"},
 	}
 
 	for _, tc := range negativeTestCases {
@@ -322,11 +361,67 @@ func TestNotContainCSIObjects(t *testing.T) {
 			require.NoError(t, err)
 			require.NotNil(t, r)
 			require.False(t, r.Ok)
-			require.Equal(t, CSIObjectsExist, r.Reason)
+			require.Equal(t, fmt.Sprintf("%s : %s", CSIObjectsExist, tc.object), r.Reason)
 		})
 	}
 }
 
+func TestNotContainTemplatedObjects(t *testing.T) {
+
+	t.Run("Objects are not found when not rendered", func(t *testing.T) {
+		opts := &CheckOptions{URI: "chart-0.1.0-v3.templated-objects", ViperConfig: viper.New(), HelmEnvSettings: cli.New()}
+		r, err := NotContainCSIObjects(opts)
+		require.NoError(t, err)
+		require.True(t, r.Ok)
+		require.Equal(t, CSIObjectsDoesNotExist, r.Reason)
+		r, err = NotContainCRDs(opts)
+		require.NoError(t, err)
+		require.True(t, r.Ok)
+		require.Equal(t, ChartDoesNotContainCRDs, r.Reason)
+	})
+
+	t.Run("Rendered objects are found", func(t *testing.T) {
+		values := map[string]interface{}{"csi": map[string]interface{}{"enabled": true}, "crd": map[string]interface{}{"enabled": true}}
+		opts := &CheckOptions{URI: "chart-0.1.0-v3.templated-objects", ViperConfig: viper.New(), HelmEnvSettings: cli.New(), Values: values}
+		r, err := NotContainCSIObjects(opts)
+		require.NoError(t, err)
+		require.False(t, r.Ok)
+		require.Equal(t, fmt.Sprintf("%s : CSIDriver/test-release.example.com (templates/csidriver.yaml)", CSIObjectsExist), r.Reason)
+		r, err = NotContainCRDs(opts)
+		require.NoError(t, err)
+		require.False(t, r.Ok)
+		require.Equal(t, fmt.Sprintf("%s : CustomResourceDefinition/widgets.example.com (templates/crd.yaml)", ChartContainCRDs), r.Reason)
+	})
+}
+
+func TestNotContainObjectsOfChartNamedCRDs(t *testing.T) {
+
+	t.Run("Sources are relative to the chart", func(t *testing.T) {
+		opts := &CheckOptions{URI: "chart-0.1.0-v3.named-crds", ViperConfig: viper.New(), HelmEnvSettings: cli.New()}
+		r, err := NotContainCSIObjects(opts)
+		require.NoError(t, err)
+		require.False(t, r.Ok)
+		require.Equal(t, fmt.Sprintf("%s : CSIDriver/test-release.example.com (templates/csidriver.yaml)", CSIObjectsExist), r.Reason)
+		r, err = NotContainCRDs(opts)
+		require.NoError(t, err)
+		require.False(t, r.Ok)
+		require.Equal(t, fmt.Sprintf("%s : CustomResourceDefinition/widgets.example.com (crds/widget.yaml)", ChartContainCRDs), r.Reason)
+	})
+
+	t.Run("Should fail when the chart cannot be rendered", func(t *testing.T) {
+		values := map[string]interface{}{"fail": true}
+		opts := &CheckOptions{URI: "chart-0.1.0-v3.named-crds", ViperConfig: viper.New(), HelmEnvSettings: cli.New(), Values: values}
+		r, err := NotContainCSIObjects(opts)
+		require.NoError(t, err)
+		require.False(t, r.Ok)
+		require.True(t, strings.HasPrefix(r.Reason, RenderFailure+" : "))
+		r, err = NotContainCRDs(opts)
+		require.NoError(t, err)
+		require.False(t, r.Ok)
+		require.True(t, strings.HasPrefix(r.Reason, RenderFailure+" : "))
+	})
+}
+
 func TestHelmLint(t *testing.T) {
 	type testCase struct {
 		description string
@@ -365,6 +460,323 @@ func TestHelmLint(t *testing.T) {
 		})
 	}
 
+	t.Run("Helm lint fails for a values scenario in the ci folder", func(t *testing.T) {
+		config := viper.New()
+		r, err := HelmLint(&CheckOptions{URI: "chart-0.1.0-v3.ci-values", ViperConfig: config, HelmEnvSettings: cli.New()})
+		require.NoError(t, err)
+		require.False(t, r.Ok)
+		require.Contains(t, r.Reason, HelmLintHasFailedPrefix)
+		require.Contains(t, r.Reason, "ci/broken-values.yaml : ")
+		require.NotContains(t, r.Reason, "ci/message-values.yaml : ")
+		require.NotContains(t, r.Reason, DefaultValuesScenario+" : ")
+	})
+
+	t.Run("Helm lint user values apply to the values scenarios in the ci folder", func(t *testing.T) {
+		config := viper.New()
+		values := map[string]interface{}{"broken": false}
+		r, err := HelmLint(&CheckOptions{URI: "chart-0.1.0-v3.ci-values", ViperConfig: config, HelmEnvSettings: cli.New(), Values: values})
+		require.NoError(t, err)
+		require.True(t, r.Ok)
+		require.Equal(t, HelmLintSuccessful, r.Reason)
+	})
+
+	t.Run("Helm lint fails on warnings when configured", func(t *testing.T) {
+		config := viper.New()
+		config.Set(LintFailOnConfigName, "warning")
+		r, err := HelmLint(&CheckOptions{URI: "chart-0.1.0-v2.lint-warning.tgz", ViperConfig: config, HelmEnvSettings: cli.New()})
+		require.NoError(t, err)
+		require.False(t, r.Ok)
+		require.Contains(t, r.Reason, "[WARNING] templates/deployment.yaml: object name does not conform")
+	})
+
+	t.Run("Helm lint fails on info messages when configured", func(t *testing.T) {
+		config := viper.New()
+		config.Set(LintFailOnConfigName, "info")
+		r, err := HelmLint(&CheckOptions{URI: "chart-0.1.0-v2.lint-info.tgz", ViperConfig: config, HelmEnvSettings: cli.New()})
+		require.NoError(t, err)
+		require.False(t, r.Ok)
+		require.Equal(t, fmt.Sprintf("%s [INFO] Chart.yaml: icon is recommended\n", HelmLintHasFailedPrefix), r.Reason)
+	})
+
+	t.Run("Helm lint errors for an unknown severity", func(t *testing.T) {
+		config := viper.New()
+		config.Set(LintFailOnConfigName, "fatal")
+		_, err := HelmLint(&CheckOptions{URI: "chart-0.1.0-v3.valid.tgz", ViperConfig: config, HelmEnvSettings: cli.New()})
+		require.Error(t, err)
+	})
+
+	t.Run("Helm lint strict mode and namespace are read from config", func(t *testing.T) {
+		config := viper.New()
+		config.Set(LintStrictConfigName, true)
+		config.Set(LintNamespaceConfigName, "example")
+		options, err := getHelmLintOptions(&CheckOptions{ViperConfig: config})
+		require.NoError(t, err)
+		require.Equal(t, helmLintOptions{Strict: true, Namespace: "example", FailOn: support.ErrorSev}, options)
+	})
+
+}
+
+func TestRunsWithRestrictedSCC(t *testing.T) {
+	type testCase struct {
+		description string
+		values      map[string]interface{}
+		ok          bool
+		reasons     []string
+	}
+
+	testCases := []testCase{
+		{description: "Restricted workloads can be installed with restricted-v2", ok: true, reasons: []string{RestrictedSCCSuccess}},
+		{description: "Workload running as root requires anyuid", values: map[string]interface{}{"anyuid": true}, ok: false,
+			reasons: []string{fmt.Sprintf("%s : CronJob/test-release-anyuid (templates/anyuid.yaml) requires the anyuid SCC : container backup runs as user 0", RestrictedSCCFailure)}},
+		{description: "Privileged workload requires privileged", values: map[string]interface{}{"privileged": true}, ok: false,
+			reasons: []string{fmt.Sprintf("%s : DaemonSet/test-release-privileged (templates/privileged.yaml) requires the privileged SCC", RestrictedSCCFailure),
+				"uses the host network", "uses the host PID namespace", "mounts host path /var/lib in volume host",
+				"container agent is privileged", "container agent adds capability SYS_ADMIN"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			r, err := CanBeInstalledWithoutClusterAdminPrivileges(&CheckOptions{URI: "chart-0.1.0-v3.security-context", ViperConfig: viper.New(), HelmEnvSettings: cli.New(), Values: tc.values})
+			require.NoError(t, err)
+			require.Equal(t, tc.ok, r.Ok)
+			for _, reason := range tc.reasons {
+				require.Contains(t, r.Reason, reason)
+			}
+			require.NotContains(t, r.Reason, "restricted (templates/restricted.yaml)")
+		})
+	}
+}
+
+func TestHasLeastPrivilegeRBAC(t *testing.T) {
+	type testCase struct {
+		description string
+		values      map[string]interface{}
+		ok          bool
+		reasons     []string
+	}
+
+	testCases := []testCase{
+		{description: "Namespaced role follows least privilege", ok: true,
+			reasons: []string{LeastPrivilegeRBACSuccess,
+				fmt.Sprintf("%s : ServiceAccount test-release-reader (namespace) : get,list,watch pods,configmaps; get secrets [test-release-credentials] : granted by Role/test-release-reader", ServiceAccountPermissions)}},
+		{description: "Cluster role with wildcards does not follow least privilege", values: map[string]interface{}{"clusterAdmin": true}, ok: false,
+			reasons: []string{
+				fmt.Sprintf("%s : ServiceAccount operators/test-release-operator (cluster-wide) : wildcard verbs : * *", LeastPrivilegeRBACFailure),
+				fmt.Sprintf("%s : ServiceAccount operators/test-release-operator (cluster-wide) : wildcard API groups : * *", LeastPrivilegeRBACFailure),
+				fmt.Sprintf("%s : ServiceAccount operators/test-release-operator (cluster-wide) : wildcard resources : * *", LeastPrivilegeRBACFailure),
+				fmt.Sprintf("%s : ServiceAccount operators/test-release-operator (cluster-wide) : access to all secrets : list secrets", LeastPrivilegeRBACFailure),
+				fmt.Sprintf("%s : ServiceAccount operators/test-release-operator (cluster-wide) : escalate verb : bind,escalate clusterroles", LeastPrivilegeRBACFailure),
+				fmt.Sprintf("%s : ServiceAccount operators/test-release-operator (cluster-wide) : bind verb : bind,escalate clusterroles", LeastPrivilegeRBACFailure),
+				fmt.Sprintf("%s : ServiceAccount operators/test-release-operator (cluster-wide) : impersonate verb : impersonate users", LeastPrivilegeRBACFailure),
+				fmt.Sprintf("%s : ServiceAccount operators/test-release-operator (cluster-wide) : bound to ClusterRole/cluster-admin", LeastPrivilegeRBACFailure),
+				fmt.Sprintf("%s : ServiceAccount operators/test-release-operator (cluster-wide) : * *; list secrets; bind,escalate clusterroles; impersonate users : granted by ClusterRole/test-release-operator, ClusterRole/cluster-admin (not created by the chart)", ServiceAccountPermissions),
+				fmt.Sprintf("%s : ClusterRole/test-release-operator (templates/clusterrole.yaml)", ClusterScopedObject),
+				fmt.Sprintf("%s : ClusterRoleBinding/test-release-admin (templates/clusterrole.yaml)", ClusterScopedObject),
+			}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			r, err := HasLeastPrivilegeRBAC(&CheckOptions{URI: "chart-0.1.0-v3.rbac", ViperConfig: viper.New(), HelmEnvSettings: cli.New(), Values: tc.values})
+			require.NoError(t, err)
+			require.Equal(t, tc.ok, r.Ok)
+			for _, reason := range tc.reasons {
+				require.Contains(t, r.Reason, reason)
+			}
+		})
+	}
+}
+
+func TestGetRBACReport(t *testing.T) {
+
+	object := func(content string) renderedObject {
+		o := renderedObject{Source: "templates/rbac.yaml"}
+		require.NoError(t, yaml.Unmarshal([]byte(content), &o.Object))
+		return o
+	}
+	objects := []renderedObject{
+		object("kind: Role\nmetadata:\n  name: pod-reader\nrules:\n  - apiGroups: ['']\n    resources: [pods]\n    verbs: [get]\n"),
+		object("kind: Role\nmetadata:\n  name: pod-lister\nrules:\n  - apiGroups: ['']\n    resources: [pods]\n    verbs: [get, list]\n"),
+		object("kind: ClusterRole\nmetadata:\n  name: any-group\nrules:\n  - apiGroups: ['*']\n    resources: [deployments]\n    verbs: [get]\n"),
+		object("kind: RoleBinding\nmetadata:\n  name: reader\nroleRef: {kind: Role, name: pod-reader}\nsubjects:\n  - {kind: ServiceAccount, name: app}\n"),
+		object("kind: RoleBinding\nmetadata:\n  name: lister\nroleRef: {kind: Role, name: pod-lister}\nsubjects:\n  - {kind: ServiceAccount, name: app}\n"),
+		object("kind: ClusterRoleBinding\nmetadata:\n  name: admins\nroleRef: {kind: ClusterRole, name: any-group}\nsubjects:\n  - {kind: Group, name: admins}\n"),
+	}
+
+	report, err := getRBACReport(objects)
+	require.NoError(t, err)
+	require.Equal(t, []string{"ServiceAccount app (namespace) : get,list pods : granted by Role/pod-reader, Role/pod-lister"}, report.Permissions)
+	require.Equal(t, []string{"ClusterRole/any-group (templates/rbac.yaml) : wildcard API groups : get deployments"}, report.Findings)
+}
+
+func TestKeywordsAreOpenshiftCategories(t *testing.T) {
+	type testCase struct {
+		description string
+		uri         string
+		categories  []string
+		ok          bool
+		reason      string
+	}
+
+	testCases := []testCase{
+		{description: "Keyword matches an OpenShift category", uri: "chart-0.1.0-v3.partner", ok: true, reason: fmt.Sprintf("%s: [Database]", KeywordsAreCategories)},
+		{description: "Chart without keywords", uri: "chart-0.1.0-v3.valid.tgz", ok: false, reason: fmt.Sprintf("%s: []", KeywordsAreNotCategories)},
+		{description: "Keyword does not match overridden categories", uri: "chart-0.1.0-v3.partner", categories: []string{"Storage"}, ok: false, reason: fmt.Sprintf("%s: [postgres database]", KeywordsAreNotCategories)},
+		{description: "Keyword matches overridden categories", uri: "chart-0.1.0-v3.partner", categories: []string{"Storage", "Postgres"}, ok: true, reason: fmt.Sprintf("%s: [Postgres]", KeywordsAreCategories)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			config := viper.New()
+			if tc.categories != nil {
+				config.Set(CategoriesConfigName, tc.categories)
+			}
+			r, err := KeywordsAreOpenshiftCategories(&CheckOptions{URI: tc.uri, ViperConfig: config, HelmEnvSettings: cli.New()})
+			require.NoError(t, err)
+			require.Equal(t, tc.ok, r.Ok)
+			require.Equal(t, tc.reason, r.Reason)
+		})
+	}
+}
+
+func TestIsCommercialAndCommunityChart(t *testing.T) {
+	type testCase struct {
+		description     string
+		uri             string
+		commercial      bool
+		commercialBasis string
+		community       bool
+		communityBasis  string
+	}
+
+	testCases := []testCase{
+		{description: "Partner chart is commercial", uri: "chart-0.1.0-v3.partner",
+			commercial: true, commercialBasis: fmt.Sprintf("%s : annotation %s is partner", ChartIsCommercial, ProviderTypeAnnotation),
+			community: false, communityBasis: fmt.Sprintf("%s : annotation %s is partner", ChartIsNotCommunity, ProviderTypeAnnotation)},
+		{description: "Chart without provider annotations is community", uri: "chart-0.1.0-v3.valid.tgz",
+			commercial: false, commercialBasis: fmt.Sprintf("%s : annotations %s and %s are not set", ChartIsNotCommercial, ProviderTypeAnnotation, ProviderAnnotation),
+			community: true, communityBasis: fmt.Sprintf("%s : annotations %s and %s are not set", ChartIsCommunity, ProviderTypeAnnotation, ProviderAnnotation)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			r, err := IsCommercialChart(&CheckOptions{URI: tc.uri, ViperConfig: viper.New(), HelmEnvSettings: cli.New()})
+			require.NoError(t, err)
+			require.Equal(t, tc.commercial, r.Ok)
+			require.Equal(t, tc.commercialBasis, r.Reason)
+
+			r, err = IsCommunityChart(&CheckOptions{URI: tc.uri, ViperConfig: viper.New(), HelmEnvSettings: cli.New()})
+			require.NoError(t, err)
+			require.Equal(t, tc.community, r.Ok)
+			require.Equal(t, tc.communityBasis, r.Reason)
+		})
+	}
+}
+
+func TestHasResourceRequirements(t *testing.T) {
+	type testCase struct {
+		description string
+		values      map[string]interface{}
+		config      map[string]interface{}
+		ok          bool
+		reason      string
+	}
+
+	testCases := []testCase{
+		{description: "Containers with resources and probes", ok: true, reason: ResourceRequirementsSuccess},
+		{description: "Container without resources or probes", ok: false,
+			values: map[string]interface{}{"resources": nil, "probes": false},
+			reason: fmt.Sprintf("%s : Deployment/test-release-web (templates/deployment.yaml) : container web : missing cpu request, missing cpu limit, missing memory request, missing memory limit, missing readiness probe, missing liveness probe", ResourceRequirementsFailure)},
+		{description: "Container limits beyond configured ratio", ok: false,
+			config: map[string]interface{}{MaxLimitRequestRatioConfigName: 1.5},
+			reason: fmt.Sprintf("%s : Deployment/test-release-web (templates/deployment.yaml) : container web : cpu limit to request ratio 2.0 exceeds 1.5, memory limit to request ratio 2.0 exceeds 1.5", ResourceRequirementsFailure)},
+		{description: "Probes not required by configuration", ok: true,
+			values: map[string]interface{}{"probes": false},
+			config: map[string]interface{}{RequireProbesConfigName: false},
+			reason: ResourceRequirementsSuccess},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			config := viper.New()
+			for key, value := range tc.config {
+				config.Set(key, value)
+			}
+			r, err := HasResourceRequirements(&CheckOptions{URI: "chart-0.1.0-v3.resources", ViperConfig: config, HelmEnvSettings: cli.New(), Values: tc.values})
+			require.NoError(t, err)
+			require.Equal(t, tc.ok, r.Ok)
+			require.Equal(t, tc.reason, r.Reason)
+		})
+	}
+}
+
+func TestDependenciesAreValid(t *testing.T) {
+
+	profileChecks := []Check{
+		{CheckId: CheckId{Name: apiChecks.HasReadme, Version: "v1.0"}, Type: apiChecks.MandatoryCheckType, Func: HasReadme},
+		{CheckId: CheckId{Name: apiChecks.IsHelmV3, Version: "v1.0"}, Type: apiChecks.OptionalCheckType, Func: IsHelmV3},
+		{CheckId: CheckId{Name: apiChecks.ChartTesting, Version: "v1.0"}, Type: apiChecks.MandatoryCheckType, Func: ChartTesting},
+	}
+
+	t.Run("Vendored dependency is valid", func(t *testing.T) {
+		r, err := DependenciesAreValid(&CheckOptions{URI: "chart-0.1.0-v3.dependencies", ViperConfig: viper.New(), HelmEnvSettings: cli.New(), ProfileChecks: profileChecks})
+		require.NoError(t, err)
+		require.True(t, r.Ok)
+		require.Equal(t, DependenciesValid, r.Reason)
+	})
+
+	t.Run("Subchart fails mandatory check when run recursively", func(t *testing.T) {
+		config := viper.New()
+		config.Set(RecursiveConfigName, true)
+		r, err := DependenciesAreValid(&CheckOptions{URI: "chart-0.1.0-v3.dependencies", ViperConfig: config, HelmEnvSettings: cli.New(), ProfileChecks: profileChecks})
+		require.NoError(t, err)
+		require.False(t, r.Ok)
+		require.Equal(t, strings.Join([]string{DependenciesNotValid,
+			fmt.Sprintf("%s : charts/sub : v1.0/has-readme", SubchartMandatoryCheckFailed)}, "\n"), r.Reason)
+		require.Len(t, r.Subcharts, 2)
+		require.Equal(t, "charts/sub", r.Subcharts[0].Subchart)
+		require.Equal(t, apiChecks.HasReadme, r.Subcharts[0].Check.CheckId.Name)
+		require.False(t, r.Subcharts[0].Result.Ok)
+		require.Equal(t, ReadmeDoesNotExist, r.Subcharts[0].Result.Reason)
+		require.Equal(t, apiChecks.IsHelmV3, r.Subcharts[1].Check.CheckId.Name)
+		require.True(t, r.Subcharts[1].Result.Ok)
+	})
+
+	t.Run("Subchart failing optional check is only reported", func(t *testing.T) {
+		config := viper.New()
+		config.Set(RecursiveConfigName, true)
+		optionalChecks := []Check{
+			{CheckId: CheckId{Name: apiChecks.HasReadme, Version: "v1.0"}, Type: apiChecks.OptionalCheckType, Func: HasReadme},
+		}
+		r, err := DependenciesAreValid(&CheckOptions{URI: "chart-0.1.0-v3.dependencies", ViperConfig: config, HelmEnvSettings: cli.New(), ProfileChecks: optionalChecks})
+		require.NoError(t, err)
+		require.True(t, r.Ok)
+		require.Equal(t, strings.Join([]string{DependenciesValid,
+			fmt.Sprintf("%s : charts/sub : v1.0/has-readme", SubchartOptionalCheckFailed)}, "\n"), r.Reason)
+		require.Len(t, r.Subcharts, 1)
+	})
+}
+
+func TestHasCompleteMetadata(t *testing.T) {
+
+	t.Run("Chart with complete metadata", func(t *testing.T) {
+		r, err := HasCompleteMetadata(&CheckOptions{URI: "chart-0.1.0-v3.metadata", ViperConfig: viper.New(), HelmEnvSettings: cli.New()})
+		require.NoError(t, err)
+		require.True(t, r.Ok)
+		require.Equal(t, MetadataComplete, r.Reason)
+	})
+
+	t.Run("Chart with incomplete metadata", func(t *testing.T) {
+		config := viper.New()
+		config.Set(RequiredMetadataConfigName, []string{HomeMetadata, MaintainersMetadata, LicenseMetadata, VersionMetadata})
+		r, err := HasCompleteMetadata(&CheckOptions{URI: "chart-0.1.0-v3.partner", ViperConfig: config, HelmEnvSettings: cli.New()})
+		require.NoError(t, err)
+		require.False(t, r.Ok)
+		require.Equal(t, strings.Join([]string{
+			fmt.Sprintf("%s : home : not set", MetadataIncomplete),
+			fmt.Sprintf("%s : maintainers : not set", MetadataIncomplete),
+			fmt.Sprintf("%s : license : annotation %s not set", MetadataIncomplete, DefaultLicenseAnnotation)}, "\n"), r.Reason)
+	})
 }
 
 func TestImageCertify(t *testing.T) {
@@ -534,7 +946,13 @@ func TestSemVers(t *testing.T) {
 		{kubeVersion: ">= 1.14.0-0", OCPRange: ">=4.2"},
 		{kubeVersion: "1.16 - 1.21", OCPRange: "4.3 - 4.8"},
 		{kubeVersion: "*", OCPRange: ">=4.1"},
-		{kubeVersion: ">=1.16.0 <1.22.0", OCPRange: "Error converting kubeVersion to an OCP range : improper constraint: >=1.16.0 <1.22.0"},
+		{kubeVersion: ">=1.16.0 <1.22.0", OCPRange: "4.3 - 4.8"},
+		{kubeVersion: ">=1.20 <1.22 || >=1.24", OCPRange: "4.7 - 4.8 || >=4.11"},
+		{kubeVersion: ">=1.22.0-0 <1.23.0-0", OCPRange: "4.9"},
+		{kubeVersion: "<1.22.0-rc.1 >=1.21", OCPRange: "4.8"},
+		{kubeVersion: ">=1.99", OCPRange: "Error converting kubeVersion to an OCP range : Failed to determine a minimum OCP version"},
+		{kubeVersion: "> 1.16 <= 1.14", OCPRange: "Error converting kubeVersion to an OCP range : Failed to determine a minimum OCP version"},
+		{kubeVersion: "not a version", OCPRange: "Error converting kubeVersion to an OCP range : improper constraint: not a version"},
 	}
 
 	for _, test := range testCases {
@@ -0,0 +1,319 @@
+package checks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/spf13/viper"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/provenance"
+
+	apiChecks "github.com/redhat-certification/chart-verifier/pkg/chartverifier/checks"
+)
+
+const (
+	// RecursiveConfigName is the key of the check configuration setting if the profile's static checks are run on
+	// each subchart.
+	RecursiveConfigName = "recursive"
+
+	repositoryTimeout = 30 * time.Second
+)
+
+// subchartCheckExclusions are checks which need more than the chart itself, such as a cluster, an image registry or a
+// provenance file, so are not run on subcharts.
+var subchartCheckExclusions = map[apiChecks.CheckName]bool{
+	apiChecks.ChartTesting:         true,
+	apiChecks.ImagesAreCertified:   true,
+	apiChecks.SignatureIsValid:     true,
+	apiChecks.DependenciesAreValid: true,
+}
+
+// isRepositoryReachable is a variable so tests can replace it.
+var isRepositoryReachable = getRepositoryReachable
+
+// getDependencyIssues returns the issues found with the dependencies declared in the chart's Chart.yaml, the file://
+// repositories being resolved relative to the chart directory, if the chart is a directory.
+func getDependencyIssues(c *chart.Chart, chartDir string) []string {
+
+	issues := make([]string, 0)
+	dependencies := c.Metadata.Dependencies
+
+	// effective names are the names subcharts are known by, the alias if set or the name otherwise.
+	names := make(map[string]bool)
+	for _, dependency := range dependencies {
+		names[dependency.Name] = true
+	}
+	effectiveNames := make(map[string]bool)
+	for _, dependency := range dependencies {
+		effectiveName := dependency.Name
+		if len(dependency.Alias) > 0 {
+			effectiveName = dependency.Alias
+			if dependency.Alias != dependency.Name && names[dependency.Alias] {
+				issues = append(issues, fmt.Sprintf("%s : alias %s conflicts with the name of another dependency", dependency.Name, dependency.Alias))
+			}
+		}
+		if effectiveNames[effectiveName] {
+			issues = append(issues, fmt.Sprintf("%s : duplicate dependency %s, set a unique alias", dependency.Name, effectiveName))
+		}
+		effectiveNames[effectiveName] = true
+	}
+
+	for _, dependency := range dependencies {
+		if _, err := semver.NewVersion(dependency.Version); err != nil {
+			issues = append(issues, fmt.Sprintf("%s : version %q is not pinned", dependency.Name, dependency.Version))
+		}
+		if c.Lock != nil {
+			if issue := getLockIssue(c.Lock, dependency); len(issue) > 0 {
+				issues = append(issues, fmt.Sprintf("%s : %s", dependency.Name, issue))
+			}
+		}
+		if isVendored(c, dependency) {
+			continue
+		}
+		if strings.HasPrefix(dependency.Repository, "file://") {
+			if err := checkLocalDependency(chartDir, dependency); err != nil {
+				issues = append(issues, fmt.Sprintf("%s : not vendored and local chart is not valid : %v", dependency.Name, err))
+			}
+		} else if err := isRepositoryReachable(dependency.Repository); err != nil {
+			issues = append(issues, fmt.Sprintf("%s : not vendored and repository is not reachable : %v", dependency.Name, err))
+		}
+	}
+
+	if len(dependencies) > 0 && c.Lock == nil {
+		issues = append(issues, "Chart.lock : file is missing")
+	} else if c.Lock != nil {
+		for _, locked := range c.Lock.Dependencies {
+			found := false
+			for _, dependency := range dependencies {
+				if locked.Name == dependency.Name && locked.Repository == dependency.Repository {
+					found = true
+					break
+				}
+			}
+			if !found {
+				issues = append(issues, fmt.Sprintf("Chart.lock : %s from %s is not a dependency in Chart.yaml", locked.Name, locked.Repository))
+			}
+		}
+		if !lockDigestMatches(c) {
+			issues = append(issues, "Chart.lock : digest does not match the dependencies in Chart.yaml, run helm dependency update")
+		}
+	}
+
+	return issues
+}
+
+// lockDigestMatches checks the digest of the lock file is that of the dependencies of the chart, as helm dependency
+// build does. The repositories of the dependencies are resolved by helm when they are repository names, such as
+// @stable, in which case the digest cannot be computed and is not checked.
+func lockDigestMatches(c *chart.Chart) bool {
+	for _, dependency := range c.Metadata.Dependencies {
+		if strings.HasPrefix(dependency.Repository, "@") || strings.HasPrefix(dependency.Repository, "alias:") {
+			return true
+		}
+	}
+	data, err := json.Marshal([2][]*chart.Dependency{c.Metadata.Dependencies, c.Lock.Dependencies})
+	if err != nil {
+		return false
+	}
+	if digest, err := provenance.Digest(bytes.NewBuffer(data)); err == nil && "sha256:"+digest == c.Lock.Digest {
+		return true
+	}
+	// the lock file of a v1 chart may have been built by helm 2, with a digest of the dependencies only.
+	if c.Metadata.APIVersion == chart.APIVersionV1 {
+		data, err = json.Marshal(map[string][]*chart.Dependency{"dependencies": c.Metadata.Dependencies})
+		if err != nil {
+			return false
+		}
+		digest, err := provenance.Digest(bytes.NewBuffer(data))
+		return err == nil && "sha256:"+digest == c.Lock.Digest
+	}
+	return false
+}
+
+// checkLocalDependency checks the chart of a file:// repository, relative to the chart directory, is the dependency.
+func checkLocalDependency(chartDir string, dependency *chart.Dependency) error {
+	if len(chartDir) == 0 {
+		return fmt.Errorf("%s can only be resolved for a chart directory", dependency.Repository)
+	}
+	dependencyDir := strings.TrimPrefix(dependency.Repository, "file://")
+	if !filepath.IsAbs(dependencyDir) {
+		dependencyDir = filepath.Join(chartDir, dependencyDir)
+	}
+	dependencyChart, err := loader.Load(dependencyDir)
+	if err != nil {
+		return err
+	}
+	if dependencyChart.Name() != dependency.Name {
+		return fmt.Errorf("%s is chart %s", dependency.Repository, dependencyChart.Name())
+	}
+	if !versionMatches(dependency.Version, dependencyChart.Metadata.Version) {
+		return fmt.Errorf("%s is version %s", dependency.Repository, dependencyChart.Metadata.Version)
+	}
+	return nil
+}
+
+// getChartDir returns the directory of a chart uri which is a local directory, or an empty string otherwise.
+func getChartDir(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || (u.Scheme != "" && u.Scheme != "file") {
+		return ""
+	}
+	if info, err := os.Stat(u.Path); err == nil && info.IsDir() {
+		return u.Path
+	}
+	return ""
+}
+
+// getLockIssue checks a dependency has a matching entry in the chart's lock file.
+func getLockIssue(lock *chart.Lock, dependency *chart.Dependency) string {
+	for _, locked := range lock.Dependencies {
+		if locked.Name != dependency.Name || locked.Repository != dependency.Repository {
+			continue
+		}
+		if !versionMatches(dependency.Version, locked.Version) {
+			return fmt.Sprintf("Chart.lock version %s does not match %s", locked.Version, dependency.Version)
+		}
+		return ""
+	}
+	return "not found in Chart.lock"
+}
+
+// isVendored checks if a dependency is in the charts directory of the chart.
+func isVendored(c *chart.Chart, dependency *chart.Dependency) bool {
+	for _, subchart := range c.Dependencies() {
+		if subchart.Name() == dependency.Name && versionMatches(dependency.Version, subchart.Metadata.Version) {
+			return true
+		}
+	}
+	return false
+}
+
+// versionMatches checks if a version satisfies a version, or a version constraint.
+func versionMatches(constraint string, version string) bool {
+	if constraint == version {
+		return true
+	}
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+	return c.Check(v)
+}
+
+// getRepositoryReachable checks a chart repository, or an OCI registry, can be reached.
+func getRepositoryReachable(repository string) error {
+
+	repoUrl, err := url.Parse(repository)
+	if err != nil {
+		return err
+	}
+
+	var checkUrl string
+	switch repoUrl.Scheme {
+	case "http", "https":
+		checkUrl = strings.TrimSuffix(repository, "/") + "/index.yaml"
+	case "oci":
+		checkUrl = fmt.Sprintf("https://%s/v2/", repoUrl.Host)
+	default:
+		return fmt.Errorf("repository %q is not a http, https or oci url", repository)
+	}
+
+	client := http.Client{Timeout: repositoryTimeout}
+	// #nosec G107
+	resp, err := client.Get(checkUrl)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// an OCI registry requiring authentication is reachable.
+	if resp.StatusCode == http.StatusOK || (repoUrl.Scheme == "oci" && resp.StatusCode == http.StatusUnauthorized) {
+		return nil
+	}
+	return fmt.Errorf("response code %d from %s", resp.StatusCode, checkUrl)
+}
+
+// discardAnnotationHolder ignores the annotations set by checks run on subcharts, which only apply to the chart itself.
+type discardAnnotationHolder struct{}
+
+func (holder *discardAnnotationHolder) SetCertifiedOpenShiftVersion(version string) {}
+
+func (holder *discardAnnotationHolder) SetEmulatedOpenShiftVersion(version string) {}
+
+func (holder *discardAnnotationHolder) GetCertifiedOpenShiftVersionFlag() string {
+	return ""
+}
+
+func (holder *discardAnnotationHolder) SetSupportedOpenShiftVersions(versions string) {}
+
+func (holder *discardAnnotationHolder) SetTestedCluster(distribution, platform, version string) {}
+
+// subchart is a chart found in the charts directory of a chart, or of one of its subcharts.
+type subchart struct {
+	Path  string
+	Chart *chart.Chart
+}
+
+func getSubcharts(c *chart.Chart, parentPath string) []subchart {
+	subcharts := make([]subchart, 0)
+	for _, dependency := range c.Dependencies() {
+		subchartPath := path.Join(parentPath, "charts", dependency.Name())
+		subcharts = append(subcharts, subchart{Path: subchartPath, Chart: dependency})
+		subcharts = append(subcharts, getSubcharts(dependency, subchartPath)...)
+	}
+	return subcharts
+}
+
+// runSubchartChecks runs the static checks of the profile on each subchart of the chart and returns the result of each
+// check run.
+func runSubchartChecks(c *chart.Chart, opts *CheckOptions) ([]SubchartResult, error) {
+
+	tempDir, err := ioutil.TempDir("", "chart-verifier-subcharts-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	results := make([]SubchartResult, 0)
+	for index, sub := range getSubcharts(c, "") {
+		subchartDir := path.Join(tempDir, fmt.Sprintf("%d", index))
+		if err = chartutil.SaveDir(sub.Chart, subchartDir); err != nil {
+			return nil, err
+		}
+		subchartOpts := *opts
+		subchartOpts.URI = path.Join(subchartDir, sub.Chart.Name())
+		subchartOpts.Values = nil
+		subchartOpts.AnnotationHolder = &discardAnnotationHolder{}
+
+		for _, check := range opts.ProfileChecks {
+			if subchartCheckExclusions[check.CheckId.Name] || check.Func == nil {
+				continue
+			}
+			subchartOpts.ViperConfig = viper.New()
+			for key, value := range check.Config {
+				subchartOpts.ViperConfig.SetDefault(key, value)
+			}
+			r, err := check.Func(&subchartOpts)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, SubchartResult{Subchart: sub.Path, Check: check, Result: r})
+		}
+	}
+	return results, nil
+}
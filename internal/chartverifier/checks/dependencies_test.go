@@ -0,0 +1,142 @@
+package checks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/provenance"
+)
+
+// setLockDigest sets the digest of the lock file of a chart, as helm dependency update does.
+func setLockDigest(t *testing.T, c *chart.Chart) {
+	data, err := json.Marshal([2][]*chart.Dependency{c.Metadata.Dependencies, c.Lock.Dependencies})
+	require.NoError(t, err)
+	digest, err := provenance.Digest(bytes.NewBuffer(data))
+	require.NoError(t, err)
+	c.Lock.Digest = "sha256:" + digest
+}
+
+func TestGetDependencyIssues(t *testing.T) {
+
+	savedIsRepositoryReachable := isRepositoryReachable
+	defer func() { isRepositoryReachable = savedIsRepositoryReachable }()
+	isRepositoryReachable = func(repository string) error {
+		if repository == "https://unreachable.example.com" {
+			return errors.New("no such host")
+		}
+		return nil
+	}
+
+	repo := "https://charts.example.com"
+	locked := func(name, version string) *chart.Dependency {
+		return &chart.Dependency{Name: name, Version: version, Repository: repo}
+	}
+	vendored := func(name, version string) *chart.Chart {
+		return &chart.Chart{Metadata: &chart.Metadata{Name: name, Version: version}}
+	}
+
+	// the chart directory has the chart of the local dependency next to it.
+	dir := t.TempDir()
+	chartDir := filepath.Join(dir, "chart")
+	require.NoError(t, chartutil.SaveDir(&chart.Chart{Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "db", Version: "1.2.3"}}, dir))
+	localLock := &chart.Lock{Dependencies: []*chart.Dependency{{Name: "db", Version: "1.2.3", Repository: "file://../db"}}}
+
+	type testCase struct {
+		description  string
+		dependencies []*chart.Dependency
+		lock         *chart.Lock
+		subcharts    []*chart.Chart
+		chartDir     string
+		issues       []string
+	}
+
+	testCases := []testCase{
+		{description: "No dependencies", issues: []string{}},
+		{description: "Pinned and locked dependency",
+			dependencies: []*chart.Dependency{{Name: "db", Version: "1.2.3", Repository: repo}},
+			lock:         &chart.Lock{Dependencies: []*chart.Dependency{locked("db", "1.2.3")}},
+			issues:       []string{}},
+		{description: "Version range is not pinned",
+			dependencies: []*chart.Dependency{{Name: "db", Version: "^1.2.0", Repository: repo}},
+			lock:         &chart.Lock{Dependencies: []*chart.Dependency{locked("db", "1.2.3")}},
+			issues:       []string{`db : version "^1.2.0" is not pinned`}},
+		{description: "Missing lock file",
+			dependencies: []*chart.Dependency{{Name: "db", Version: "1.2.3", Repository: repo}},
+			issues:       []string{"Chart.lock : file is missing"}},
+		{description: "Lock file does not match",
+			dependencies: []*chart.Dependency{{Name: "db", Version: "1.2.3", Repository: repo}, {Name: "cache", Version: "2.0.0", Repository: repo}},
+			lock:         &chart.Lock{Dependencies: []*chart.Dependency{locked("db", "1.2.2"), locked("queue", "1.0.0")}},
+			issues: []string{"db : Chart.lock version 1.2.2 does not match 1.2.3", "cache : not found in Chart.lock",
+				"Chart.lock : queue from https://charts.example.com is not a dependency in Chart.yaml"}},
+		{description: "Unreachable repository of vendored dependency",
+			dependencies: []*chart.Dependency{{Name: "db", Version: "1.2.3", Repository: "https://unreachable.example.com"}},
+			lock:         &chart.Lock{Dependencies: []*chart.Dependency{{Name: "db", Version: "1.2.3", Repository: "https://unreachable.example.com"}}},
+			subcharts:    []*chart.Chart{vendored("db", "1.2.3")},
+			issues:       []string{}},
+		{description: "Unreachable repository of dependency which is not vendored",
+			dependencies: []*chart.Dependency{{Name: "db", Version: "1.2.3", Repository: "https://unreachable.example.com"}},
+			lock:         &chart.Lock{Dependencies: []*chart.Dependency{{Name: "db", Version: "1.2.3", Repository: "https://unreachable.example.com"}}},
+			subcharts:    []*chart.Chart{vendored("db", "1.0.0")},
+			issues:       []string{"db : not vendored and repository is not reachable : no such host"}},
+		{description: "Lock file digest does not match",
+			dependencies: []*chart.Dependency{{Name: "db", Version: "1.2.3", Repository: repo}},
+			lock:         &chart.Lock{Digest: "sha256:0", Dependencies: []*chart.Dependency{locked("db", "1.2.3")}},
+			issues:       []string{"Chart.lock : digest does not match the dependencies in Chart.yaml, run helm dependency update"}},
+		{description: "Local dependency resolved relative to the chart directory",
+			dependencies: []*chart.Dependency{{Name: "db", Version: "1.2.3", Repository: "file://../db"}},
+			lock:         localLock,
+			chartDir:     chartDir,
+			issues:       []string{}},
+		{description: "Local dependency of another version",
+			dependencies: []*chart.Dependency{{Name: "db", Version: "1.2.4", Repository: "file://../db"}},
+			lock:         &chart.Lock{Dependencies: []*chart.Dependency{{Name: "db", Version: "1.2.4", Repository: "file://../db"}}},
+			chartDir:     chartDir,
+			issues:       []string{"db : not vendored and local chart is not valid : file://../db is version 1.2.3"}},
+		{description: "Local dependency of a chart which is not a directory",
+			dependencies: []*chart.Dependency{{Name: "db", Version: "1.2.3", Repository: "file://../db"}},
+			lock:         localLock,
+			issues:       []string{"db : not vendored and local chart is not valid : file://../db can only be resolved for a chart directory"}},
+		{description: "Duplicate and conflicting aliases",
+			dependencies: []*chart.Dependency{{Name: "db", Version: "1.2.3", Repository: repo}, {Name: "db", Version: "1.2.3", Repository: repo},
+				{Name: "cache", Alias: "db", Version: "2.0.0", Repository: repo}},
+			lock: &chart.Lock{Dependencies: []*chart.Dependency{locked("db", "1.2.3"), locked("cache", "2.0.0")}},
+			issues: []string{"db : duplicate dependency db, set a unique alias", "cache : alias db conflicts with the name of another dependency",
+				"cache : duplicate dependency db, set a unique alias"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			c := &chart.Chart{Metadata: &chart.Metadata{Name: "chart", Version: "0.1.0", Dependencies: tc.dependencies}, Lock: tc.lock}
+			if tc.lock != nil && len(tc.lock.Digest) == 0 {
+				setLockDigest(t, c)
+			}
+			c.SetDependencies(tc.subcharts...)
+			require.Equal(t, tc.issues, getDependencyIssues(c, tc.chartDir))
+		})
+	}
+}
+
+func TestGetRepositoryReachable(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/charts/index.yaml" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	require.NoError(t, getRepositoryReachable(server.URL+"/charts"))
+	require.NoError(t, getRepositoryReachable(server.URL+"/charts/"))
+	require.Error(t, getRepositoryReachable(server.URL+"/missing"))
+	require.Error(t, getRepositoryReachable("file://../db"))
+}
@@ -0,0 +1,62 @@
+package checks
+
+import (
+	"fmt"
+
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier/utils"
+	"github.com/redhat-certification/chart-verifier/internal/tool"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+// emulatedVersionHolder records the OpenShift version set by chart testing as emulated, so it is not certified.
+type emulatedVersionHolder struct {
+	AnnotationHolder
+}
+
+func (h emulatedVersionHolder) SetCertifiedOpenShiftVersion(version string) {
+	if version == "N/A" {
+		h.AnnotationHolder.SetCertifiedOpenShiftVersion(version)
+		return
+	}
+	h.AnnotationHolder.SetEmulatedOpenShiftVersion(version)
+}
+
+// startEphemeralCluster starts a kind cluster running the Kubernetes version of the OpenShift version, the latest
+// version available if it is not set.
+func startEphemeralCluster(openShiftVersion string) (*tool.EphemeralCluster, error) {
+	kubeVersion, err := tool.GetEphemeralKubeVersion(openShiftVersion)
+	if err != nil {
+		return nil, err
+	}
+	cluster, err := tool.NewEphemeralCluster(kubeVersion, tool.NewProcessExecutor(false))
+	if err != nil {
+		return nil, err
+	}
+	if err = cluster.Start(); err != nil {
+		return nil, err
+	}
+	return cluster, nil
+}
+
+// stopEphemeralCluster tears the cluster down, unless it is kept for debugging a failed chart test.
+func stopEphemeralCluster(cluster *tool.EphemeralCluster, keep bool) {
+	if keep {
+		utils.LogWarning(fmt.Sprintf("Ephemeral cluster %s has been kept for debugging, kubeconfig: %s, delete it with: kind delete cluster --name %s", cluster.Name, cluster.KubeConfig, cluster.Name))
+		return
+	}
+	cluster.Stop()
+}
+
+// getEphemeralEnvSettings returns the helm settings to reach the ephemeral cluster through its kubeconfig, keeping
+// the namespace and repository settings in use.
+func getEphemeralEnvSettings(envSettings *cli.EnvSettings, kubeConfig string) *cli.EnvSettings {
+	settings := cli.New()
+	settings.KubeConfig = kubeConfig
+	settings.KubeContext = ""
+	settings.SetNamespace(envSettings.Namespace())
+	settings.Debug = envSettings.Debug
+	settings.RegistryConfig = envSettings.RegistryConfig
+	settings.RepositoryConfig = envSettings.RepositoryConfig
+	settings.RepositoryCache = envSettings.RepositoryCache
+	return settings
+}
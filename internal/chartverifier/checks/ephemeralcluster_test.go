@@ -0,0 +1,60 @@
+package checks
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/redhat-certification/chart-verifier/internal/tool"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+func TestEmulatedVersionHolder(t *testing.T) {
+
+	holder := &testAnnotationHolder{}
+	require.NoError(t, setOCVersion(emulatedVersionHolder{holder}, cli.New(), getVersionGood))
+	require.Equal(t, "4.7.9", holder.EmulatedOpenShiftVersion)
+	require.Empty(t, holder.OpenShiftVersion)
+
+	require.Error(t, setOCVersion(emulatedVersionHolder{holder}, cli.New(), getVersionError))
+	require.Equal(t, "N/A", holder.OpenShiftVersion)
+}
+
+const ephemeralKubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: kind-chart-verifier
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: kind-chart-verifier
+  context:
+    cluster: kind-chart-verifier
+    user: kind-chart-verifier
+current-context: kind-chart-verifier
+users:
+- name: kind-chart-verifier
+`
+
+func TestGetEphemeralEnvSettings(t *testing.T) {
+
+	envSettings := cli.New()
+	envSettings.SetNamespace("charts")
+	envSettings.KubeContext = "production"
+	envSettings.RegistryConfig = "/tmp/registry.json"
+
+	kubeConfig, err := ioutil.TempFile("", "kubeconfig-*")
+	require.NoError(t, err)
+	defer os.Remove(kubeConfig.Name())
+	_, err = kubeConfig.WriteString(ephemeralKubeConfig)
+	require.NoError(t, err)
+	kubeConfig.Close()
+
+	settings := getEphemeralEnvSettings(envSettings, kubeConfig.Name())
+	require.Equal(t, kubeConfig.Name(), settings.KubeConfig)
+	require.Empty(t, settings.KubeContext)
+	require.Equal(t, "charts", settings.Namespace())
+	require.Equal(t, "/tmp/registry.json", settings.RegistryConfig)
+	require.Equal(t, kubeConfig.Name(), tool.GetClientConfig(settings).ConfigAccess().GetExplicitFile())
+}
@@ -18,6 +18,8 @@ package checks
 
 import (
 	"bufio"
+	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -26,8 +28,11 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 
+	"github.com/imdario/mergo"
 	"gopkg.in/yaml.v3"
 
 	"helm.sh/helm/v3/pkg/chartutil"
@@ -99,6 +104,8 @@ type ChartCacheItem struct {
 }
 
 type chartCache struct {
+	// mutex guards the chart map, charts being verified concurrently.
+	mutex    sync.Mutex
 	chartMap map[string]ChartCacheItem
 }
 
@@ -113,6 +120,8 @@ func (c *chartCache) MakeKey(uri string) string {
 }
 
 func (c *chartCache) Get(uri string) (ChartCacheItem, bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 	if item, ok := c.chartMap[c.MakeKey(uri)]; !ok {
 		return ChartCacheItem{}, false, nil
 	} else {
@@ -136,7 +145,9 @@ func (c *chartCache) Add(opts *CheckOptions, chrt *chart.Chart) (ChartCacheItem,
 	if err = chartutil.SaveDir(chrt, chartCacheDir); err != nil {
 		return ChartCacheItem{}, err
 	}
+	c.mutex.Lock()
 	c.chartMap[key] = cacheItem
+	c.mutex.Unlock()
 	return cacheItem, nil
 }
 
@@ -194,8 +205,72 @@ func IsChartNotFound(err error) bool {
 	return ok
 }
 
+// valuesScenario is a named set of values a render based check is performed with.
+type valuesScenario struct {
+	Name   string
+	Values map[string]interface{}
+}
+
+// getValuesScenarios returns the values scenarios render based checks should be performed with: the values informed by
+// the user, and for each values file found in the chart's 'ci' folder the contents of that file with the values informed
+// by the user applied on top of it.
+func getValuesScenarios(opts *CheckOptions) ([]valuesScenario, error) {
+	c, _, err := LoadChartFromURI(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	scenarios := []valuesScenario{{Name: DefaultValuesScenario, Values: opts.Values}}
+
+	var ciValuesFiles []*chart.File
+	for _, f := range c.Files {
+		if path.Dir(f.Name) == CIValuesDir && strings.HasSuffix(f.Name, CIValuesFileSuffix) {
+			ciValuesFiles = append(ciValuesFiles, f)
+		}
+	}
+	sort.Slice(ciValuesFiles, func(i, j int) bool { return ciValuesFiles[i].Name < ciValuesFiles[j].Name })
+
+	for _, f := range ciValuesFiles {
+		vals, err := chartutil.ReadValues(f.Data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading values file %s", f.Name)
+		}
+		scenarioValues := map[string]interface{}(vals)
+		if len(opts.Values) > 0 {
+			if err = mergo.MergeWithOverwrite(&scenarioValues, opts.Values); err != nil {
+				return nil, errors.Wrapf(err, "merging extra values into values file %s", f.Name)
+			}
+		}
+		scenarios = append(scenarios, valuesScenario{Name: f.Name, Values: scenarioValues})
+	}
+
+	return scenarios, nil
+}
+
+// scenarioReason prefixes the given reason with the name of the scenario it was found in; the reason is left untouched
+// when the chart only has the default scenario.
+func scenarioReason(scenarios []valuesScenario, scenario valuesScenario, reason string) string {
+	if len(scenarios) < 2 {
+		return reason
+	}
+	return fmt.Sprintf("%s : %s", scenario.Name, reason)
+}
+
 func getImageReferences(chartUri string, vals map[string]interface{}, kubeVersionString string) ([]string, error) {
 
+	txt, err := renderManifests(chartUri, vals, kubeVersionString)
+	if err != nil {
+		return nil, err
+	}
+
+	return getImagesFromContent(txt)
+
+}
+
+// renderManifests renders the chart templates, including CRDs and hooks, as 'helm template' would for the given kube
+// version; the latest known kube version is used if one is not specified.
+func renderManifests(chartUri string, vals map[string]interface{}, kubeVersionString string) (string, error) {
+
 	capabilities := chartutil.DefaultCapabilities
 
 	if kubeVersionString == "" {
@@ -203,7 +278,7 @@ func getImageReferences(chartUri string, vals map[string]interface{}, kubeVersio
 	}
 	kubeVersion, err := chartutil.ParseKubeVersion(kubeVersionString)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	capabilities.KubeVersion = *kubeVersion
@@ -218,13 +293,114 @@ func getImageReferences(chartUri string, vals map[string]interface{}, kubeVersio
 	mem.SetNamespace("TestNamespace")
 	actionConfig.Releases = storage.Init(mem)
 
-	txt, err := actions.RenderManifests("test-release", chartUri, vals, actionConfig)
+	return actions.RenderManifests("test-release", chartUri, vals, actionConfig)
+
+}
+
+// renderedObject is a kubernetes object found in the rendered chart, along with the template it was rendered from.
+type renderedObject struct {
+	Source string
+	Object map[string]interface{}
+}
+
+// Kind returns the kind of the rendered object.
+func (o renderedObject) Kind() string {
+	kind, _ := o.Object["kind"].(string)
+	return kind
+}
+
+// Name returns the name of the rendered object.
+func (o renderedObject) Name() string {
+	if metadata, ok := o.Object["metadata"].(map[string]interface{}); ok {
+		name, _ := metadata["name"].(string)
+		return name
+	}
+	return ""
+}
+
+// String identifies the rendered object in check reasons, for example "Deployment/my-app (templates/deployment.yaml)".
+func (o renderedObject) String() string {
+	return fmt.Sprintf("%s/%s (%s)", o.Kind(), o.Name(), o.Source)
+}
+
+// Decode converts the rendered object, or the field of it found at the given path, into a typed kubernetes object.
+func (o renderedObject) Decode(into interface{}, fieldPath ...string) (bool, error) {
+	var field interface{} = o.Object
+	for _, name := range fieldPath {
+		fields, ok := field.(map[string]interface{})
+		if !ok {
+			return false, nil
+		}
+		field = fields[name]
+	}
+	if field == nil {
+		return false, nil
+	}
+
+	fieldJson, err := json.Marshal(field)
+	if err != nil {
+		return false, errors.Wrapf(err, "reading %s", o)
+	}
+	if err = json.Unmarshal(fieldJson, into); err != nil {
+		return false, errors.Wrapf(err, "reading %s", o)
+	}
+	return true, nil
+}
+
+var (
+	manifestSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+	manifestSource    = regexp.MustCompile(`(?m)^# Source: (.+)$`)
+)
+
+// getRenderedObjects renders the chart and returns each kubernetes object found, in the order they were rendered.
+func getRenderedObjects(opts *CheckOptions, vals map[string]interface{}, kubeVersionString string) ([]renderedObject, error) {
+
+	chrt, _, err := LoadChartFromURI(opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return getImagesFromContent(txt)
+	txt, err := renderManifests(opts.URI, vals, kubeVersionString)
+	if err != nil {
+		return nil, err
+	}
+
+	return getObjectsFromContent(txt, chrt)
+}
+
+// getObjectsFromContent returns the objects of the rendered chart, each with the path of the file it was rendered
+// from relative to the chart.
+func getObjectsFromContent(content string, chrt *chart.Chart) ([]renderedObject, error) {
+
+	// CRDs are rendered with their path relative to the chart, templates with the chart name as prefix.
+	crdFiles := make(map[string]bool)
+	for _, crd := range chrt.CRDs() {
+		crdFiles[crd.Name] = true
+	}
+
+	objects := make([]renderedObject, 0)
+	for _, manifest := range manifestSeparator.Split(content, -1) {
+		if len(strings.TrimSpace(manifest)) == 0 {
+			continue
+		}
+		source := ""
+		if match := manifestSource.FindStringSubmatch(manifest); match != nil {
+			source = strings.TrimSpace(match[1])
+			if !crdFiles[source] {
+				source = strings.TrimPrefix(source, chrt.Name()+"/")
+			}
+		}
+		object := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(manifest), &object); err != nil {
+			return nil, errors.Wrapf(err, "parsing manifest rendered from %s", source)
+		}
+		if len(object) == 0 {
+			continue
+		}
+		objects = append(objects, renderedObject{Source: source, Object: object})
+	}
 
+	return objects, nil
 }
 
 func getImagesFromContent(content string) ([]string, error) {
@@ -189,3 +189,28 @@ func TestLongLineTemplate(t *testing.T) {
 	require.Contains(t, images, "1.1.2/cv-test/image2:tag-223")
 
 }
+
+func TestValuesScenarios(t *testing.T) {
+
+	t.Run("Chart without ci folder only has the default scenario", func(t *testing.T) {
+		scenarios, err := getValuesScenarios(&CheckOptions{URI: "chart-0.1.0-v3.valid.tgz", ViperConfig: viper.New(), HelmEnvSettings: cli.New()})
+		require.NoError(t, err)
+		require.Len(t, scenarios, 1)
+		require.Equal(t, DefaultValuesScenario, scenarios[0].Name)
+		require.Equal(t, "reason", scenarioReason(scenarios, scenarios[0], "reason"))
+	})
+
+	t.Run("Chart with ci folder has a scenario per values file", func(t *testing.T) {
+		values := map[string]interface{}{"message": "hello from user"}
+		scenarios, err := getValuesScenarios(&CheckOptions{URI: "chart-0.1.0-v3.ci-values", ViperConfig: viper.New(), HelmEnvSettings: cli.New(), Values: values})
+		require.NoError(t, err)
+		require.Len(t, scenarios, 3)
+		require.Equal(t, DefaultValuesScenario, scenarios[0].Name)
+		require.Equal(t, "ci/broken-values.yaml", scenarios[1].Name)
+		require.Equal(t, true, scenarios[1].Values["broken"])
+		require.Equal(t, "hello from user", scenarios[1].Values["message"])
+		require.Equal(t, "ci/message-values.yaml", scenarios[2].Name)
+		require.Equal(t, "hello from user", scenarios[2].Values["message"])
+		require.Equal(t, "ci/broken-values.yaml : reason", scenarioReason(scenarios, scenarios[1], "reason"))
+	})
+}
@@ -0,0 +1,87 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/lint"
+	"helm.sh/helm/v3/pkg/lint/support"
+)
+
+const (
+	// LintStrictConfigName is the key of the check configuration setting if helm lint runs in strict mode.
+	LintStrictConfigName = "strict"
+	// LintNamespaceConfigName is the key of the check configuration setting the namespace the chart is linted in.
+	LintNamespaceConfigName = "namespace"
+	// LintFailOnConfigName is the key of the check configuration setting the lowest severity of a lint message which
+	// fails the check: info, warning or error.
+	LintFailOnConfigName = "failOn"
+
+	DefaultLintNamespace = "default"
+	DefaultLintFailOn    = "error"
+)
+
+// lintSeverities maps the severity names used in the check configuration to helm lint severities.
+var lintSeverities = map[string]int{
+	"info":    support.InfoSev,
+	"warning": support.WarningSev,
+	"error":   support.ErrorSev,
+}
+
+// helmLintOptions are the settings helm lint is run with.
+type helmLintOptions struct {
+	Strict    bool
+	Namespace string
+	FailOn    int
+}
+
+// lintFinding is a helm lint message.
+type lintFinding struct {
+	Severity int
+	Path     string
+	Message  string
+}
+
+func (f lintFinding) String() string {
+	return fmt.Sprintf("[%s] %s: %s", strings.ToUpper(getSeverityName(f.Severity)), f.Path, f.Message)
+}
+
+func getSeverityName(severity int) string {
+	for name, value := range lintSeverities {
+		if value == severity {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+func getHelmLintOptions(opts *CheckOptions) (helmLintOptions, error) {
+	options := helmLintOptions{Namespace: DefaultLintNamespace, FailOn: lintSeverities[DefaultLintFailOn]}
+	if opts.ViperConfig == nil {
+		return options, nil
+	}
+	options.Strict = opts.ViperConfig.GetBool(LintStrictConfigName)
+	if namespace := opts.ViperConfig.GetString(LintNamespaceConfigName); len(namespace) > 0 {
+		options.Namespace = namespace
+	}
+	if failOn := opts.ViperConfig.GetString(LintFailOnConfigName); len(failOn) > 0 {
+		severity, ok := lintSeverities[strings.ToLower(failOn)]
+		if !ok {
+			return options, fmt.Errorf("helm lint %s %q is not one of info, warning or error", LintFailOnConfigName, failOn)
+		}
+		options.FailOn = severity
+	}
+	return options, nil
+}
+
+// getLintFindings runs helm lint on the chart and returns the messages at, or above, the fail on severity.
+func getLintFindings(chartPath string, values map[string]interface{}, options helmLintOptions) []lintFinding {
+	linter := lint.All(chartPath, values, options.Namespace, options.Strict)
+	findings := make([]lintFinding, 0)
+	for _, message := range linter.Messages {
+		if message.Severity >= options.FailOn {
+			findings = append(findings, lintFinding{Severity: message.Severity, Path: message.Path, Message: message.Err.Error()})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,289 @@
+package checks
+
+import (
+	"embed"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/chart"
+
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier/utils"
+)
+
+//go:embed spdxLicenses.yaml
+var spdxContent embed.FS
+
+const (
+	// RequiredAnnotationsConfigName is the key of the check configuration listing the annotations a chart requires.
+	RequiredAnnotationsConfigName = "annotations"
+	// RequiredMetadataConfigName is the key of the check configuration listing the metadata fields a chart requires.
+	RequiredMetadataConfigName = "required"
+	// LicenseAnnotationConfigName is the key of the check configuration setting the annotation holding the chart's
+	// SPDX license expression.
+	LicenseAnnotationConfigName = "licenseAnnotation"
+
+	DefaultLicenseAnnotation = "artifacthub.io/license"
+
+	HomeMetadata        = "home"
+	SourcesMetadata     = "sources"
+	MaintainersMetadata = "maintainers"
+	IconMetadata        = "icon"
+	LicenseMetadata     = "license"
+	AppVersionMetadata  = "appVersion"
+	VersionMetadata     = "version"
+
+	iconTimeout = 30 * time.Second
+)
+
+// metadataFields are the metadata fields required when the check configuration does not list them.
+var metadataFields = []string{HomeMetadata, SourcesMetadata, MaintainersMetadata, IconMetadata, LicenseMetadata,
+	AppVersionMetadata, VersionMetadata}
+
+// semverRegexp is the regular expression suggested by https://semver.org for a semantic version.
+var semverRegexp = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// spdxLicenses and spdxExceptions hold the SPDX identifiers, in lower case, accepted in a license expression.
+var spdxLicenses map[string]bool
+var spdxExceptions map[string]bool
+
+type spdxList struct {
+	Licenses   []string `yaml:"licenses"`
+	Exceptions []string `yaml:"exceptions"`
+}
+
+func init() {
+	yamlFile, err := spdxContent.ReadFile("spdxLicenses.yaml")
+	if err != nil {
+		utils.LogError(fmt.Sprintf("Error reading content of spdxLicenses.yaml: %v", err))
+		return
+	}
+
+	list := spdxList{}
+	err = yaml.Unmarshal(yamlFile, &list)
+	if err != nil {
+		utils.LogError(fmt.Sprintf("Error reading content of spdxLicenses.yaml: %v", err))
+		return
+	}
+	spdxLicenses = make(map[string]bool)
+	for _, license := range list.Licenses {
+		spdxLicenses[strings.ToLower(license)] = true
+	}
+	spdxExceptions = make(map[string]bool)
+	for _, exception := range list.Exceptions {
+		spdxExceptions[strings.ToLower(exception)] = true
+	}
+}
+
+// isIconReachable is a variable so tests can replace it.
+var isIconReachable = getIconReachable
+
+// getRequiredAnnotations returns the annotations listed in the check configuration, which is set by the profile, or
+// the default required annotations if the configuration does not list them.
+func getRequiredAnnotations(opts *CheckOptions) []string {
+	if opts.ViperConfig != nil && opts.ViperConfig.IsSet(RequiredAnnotationsConfigName) {
+		return opts.ViperConfig.GetStringSlice(RequiredAnnotationsConfigName)
+	}
+	return requiredAnnotations[:]
+}
+
+// getRequiredMetadata returns the metadata fields listed in the check configuration, or all metadata fields if none
+// are listed.
+func getRequiredMetadata(opts *CheckOptions) []string {
+	if opts.ViperConfig != nil {
+		if required := opts.ViperConfig.GetStringSlice(RequiredMetadataConfigName); len(required) > 0 {
+			return required
+		}
+	}
+	return metadataFields
+}
+
+func getLicenseAnnotation(opts *CheckOptions) string {
+	if opts.ViperConfig != nil {
+		if annotation := opts.ViperConfig.GetString(LicenseAnnotationConfigName); len(annotation) > 0 {
+			return annotation
+		}
+	}
+	return DefaultLicenseAnnotation
+}
+
+// getMetadataIssues returns the issues found with the required metadata fields of the chart's Chart.yaml.
+func getMetadataIssues(metadata *chart.Metadata, required []string, licenseAnnotation string) []string {
+
+	issues := make([]string, 0)
+	for _, field := range required {
+		switch field {
+		case HomeMetadata:
+			if len(strings.TrimSpace(metadata.Home)) == 0 {
+				issues = append(issues, "home : not set")
+			}
+		case SourcesMetadata:
+			if len(metadata.Sources) == 0 {
+				issues = append(issues, "sources : not set")
+			}
+		case MaintainersMetadata:
+			if len(metadata.Maintainers) == 0 {
+				issues = append(issues, "maintainers : not set")
+			}
+			for _, maintainer := range metadata.Maintainers {
+				if maintainer != nil && len(strings.TrimSpace(maintainer.Email)) == 0 {
+					issues = append(issues, fmt.Sprintf("maintainers : %s has no email", maintainer.Name))
+				}
+			}
+		case IconMetadata:
+			if len(strings.TrimSpace(metadata.Icon)) == 0 {
+				issues = append(issues, "icon : not set")
+			} else if err := isIconReachable(metadata.Icon); err != nil {
+				issues = append(issues, fmt.Sprintf("icon : %v", err))
+			}
+		case LicenseMetadata:
+			license, ok := metadata.Annotations[licenseAnnotation]
+			if !ok {
+				issues = append(issues, fmt.Sprintf("license : annotation %s not set", licenseAnnotation))
+			} else if err := validateSPDXExpression(license); err != nil {
+				issues = append(issues, fmt.Sprintf("license : %q is not a valid SPDX expression : %v", license, err))
+			}
+		case AppVersionMetadata:
+			if len(strings.TrimSpace(metadata.AppVersion)) == 0 {
+				issues = append(issues, "appVersion : not set")
+			}
+		case VersionMetadata:
+			if !semverRegexp.MatchString(metadata.Version) {
+				issues = append(issues, fmt.Sprintf("version : %q is not a semantic version", metadata.Version))
+			}
+		default:
+			issues = append(issues, fmt.Sprintf("%s : unknown metadata field", field))
+		}
+	}
+	return issues
+}
+
+// getIconReachable checks an icon is a data URI of an image, or a http or https url of an image which can be fetched.
+func getIconReachable(icon string) error {
+
+	if strings.HasPrefix(icon, "data:") {
+		if !strings.HasPrefix(icon, "data:image/") {
+			return fmt.Errorf("data uri is not an image")
+		}
+		return nil
+	}
+
+	iconUrl, err := url.Parse(icon)
+	if err != nil {
+		return err
+	}
+	if iconUrl.Scheme != "http" && iconUrl.Scheme != "https" {
+		return fmt.Errorf("%q is not a http, https or data url", icon)
+	}
+
+	client := http.Client{Timeout: iconTimeout}
+	// #nosec G107
+	resp, err := client.Get(icon)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("response code %d from %s", resp.StatusCode, icon)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "image/") {
+		return fmt.Errorf("content type %q from %s is not an image", contentType, icon)
+	}
+	return nil
+}
+
+// validateSPDXExpression checks a license is a valid SPDX license expression, for example
+// "Apache-2.0 OR (MIT AND GPL-2.0-only WITH Classpath-exception-2.0)".
+func validateSPDXExpression(expression string) error {
+	tokens := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression))
+	if len(tokens) == 0 {
+		return fmt.Errorf("expression is empty")
+	}
+	parser := &spdxParser{tokens: tokens}
+	if err := parser.expression(); err != nil {
+		return err
+	}
+	if parser.position < len(tokens) {
+		return fmt.Errorf("unexpected %q", tokens[parser.position])
+	}
+	return nil
+}
+
+// spdxParser is a recursive descent parser of the SPDX license expression grammar.
+type spdxParser struct {
+	tokens   []string
+	position int
+}
+
+func (p *spdxParser) next() string {
+	if p.position >= len(p.tokens) {
+		return ""
+	}
+	token := p.tokens[p.position]
+	p.position++
+	return token
+}
+
+func (p *spdxParser) peek() string {
+	if p.position >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.position]
+}
+
+// expression parses: term { ( "AND" | "OR" ) term }
+func (p *spdxParser) expression() error {
+	if err := p.term(); err != nil {
+		return err
+	}
+	for p.peek() == "AND" || p.peek() == "OR" {
+		p.next()
+		if err := p.term(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// term parses: "(" expression ")" | license [ "WITH" exception ]
+func (p *spdxParser) term() error {
+	token := p.next()
+	switch token {
+	case "":
+		return fmt.Errorf("unexpected end of expression")
+	case "(":
+		if err := p.expression(); err != nil {
+			return err
+		}
+		if p.next() != ")" {
+			return fmt.Errorf("missing )")
+		}
+		return nil
+	}
+	if !isSPDXLicense(token) {
+		return fmt.Errorf("unknown license %q", token)
+	}
+	if p.peek() == "WITH" {
+		p.next()
+		exception := p.next()
+		if !spdxExceptions[strings.ToLower(exception)] {
+			return fmt.Errorf("unknown license exception %q", exception)
+		}
+	}
+	return nil
+}
+
+// isSPDXLicense checks a license is a known SPDX license identifier, optionally followed by "+", or a user defined
+// LicenseRef.
+func isSPDXLicense(license string) bool {
+	if strings.HasPrefix(license, "LicenseRef-") || strings.HasPrefix(license, "DocumentRef-") {
+		return true
+	}
+	return spdxLicenses[strings.ToLower(strings.TrimSuffix(license, "+"))]
+}
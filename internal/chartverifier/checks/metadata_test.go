@@ -0,0 +1,139 @@
+package checks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestValidateSPDXExpression(t *testing.T) {
+
+	valid := []string{
+		"Apache-2.0",
+		"mit",
+		"GPL-2.0-or-later",
+		"MPL-2.0+",
+		"LicenseRef-Proprietary",
+		"Apache-2.0 OR MIT",
+		"Apache-2.0 AND (MIT OR BSD-3-Clause)",
+		"GPL-2.0-only WITH Classpath-exception-2.0",
+		"(Apache-2.0)",
+		"Elastic-2.0",
+		"GPL-2.0",
+		"Apache-1.0",
+		"Apache-2.0 WITH LLVM-exception",
+	}
+	for _, expression := range valid {
+		t.Run(expression, func(t *testing.T) {
+			require.NoError(t, validateSPDXExpression(expression))
+		})
+	}
+
+	invalid := []string{
+		"",
+		"Apache 2.0",
+		"Not-A-License",
+		"Apache-2.0 OR",
+		"(Apache-2.0 OR MIT",
+		"Apache-2.0 MIT",
+		"GPL-2.0-only WITH Not-An-Exception",
+	}
+	for _, expression := range invalid {
+		t.Run(expression, func(t *testing.T) {
+			require.Error(t, validateSPDXExpression(expression))
+		})
+	}
+}
+
+func TestGetRequiredAnnotations(t *testing.T) {
+
+	require.Equal(t, []string{"charts.openshift.io/name"}, getRequiredAnnotations(&CheckOptions{}))
+	require.Equal(t, []string{"charts.openshift.io/name"}, getRequiredAnnotations(&CheckOptions{ViperConfig: viper.New()}))
+
+	config := viper.New()
+	config.SetDefault(RequiredAnnotationsConfigName, []interface{}{"charts.openshift.io/name", "charts.openshift.io/provider"})
+	require.Equal(t, []string{"charts.openshift.io/name", "charts.openshift.io/provider"}, getRequiredAnnotations(&CheckOptions{ViperConfig: config}))
+}
+
+func TestGetMetadataIssues(t *testing.T) {
+
+	savedIsIconReachable := isIconReachable
+	defer func() { isIconReachable = savedIsIconReachable }()
+	isIconReachable = func(icon string) error {
+		return nil
+	}
+
+	complete := func() *chart.Metadata {
+		return &chart.Metadata{
+			Name:        "chart",
+			Version:     "1.2.3",
+			AppVersion:  "4.5.6",
+			Home:        "https://example.com",
+			Sources:     []string{"https://example.com/source"},
+			Icon:        "https://example.com/icon.png",
+			Maintainers: []*chart.Maintainer{{Name: "maintainer", Email: "maintainer@example.com"}},
+			Annotations: map[string]string{DefaultLicenseAnnotation: "Apache-2.0"},
+		}
+	}
+
+	t.Run("Complete metadata", func(t *testing.T) {
+		require.Empty(t, getMetadataIssues(complete(), metadataFields, DefaultLicenseAnnotation))
+	})
+
+	t.Run("Incomplete metadata", func(t *testing.T) {
+		metadata := complete()
+		metadata.Home = ""
+		metadata.Sources = nil
+		metadata.Icon = ""
+		metadata.AppVersion = ""
+		metadata.Version = "1.2"
+		metadata.Maintainers = []*chart.Maintainer{{Name: "maintainer"}}
+		metadata.Annotations = map[string]string{DefaultLicenseAnnotation: "Apache 2"}
+		require.Equal(t, []string{
+			"home : not set",
+			"sources : not set",
+			"maintainers : maintainer has no email",
+			"icon : not set",
+			`license : "Apache 2" is not a valid SPDX expression : unknown license "Apache"`,
+			"appVersion : not set",
+			`version : "1.2" is not a semantic version`,
+		}, getMetadataIssues(metadata, metadataFields, DefaultLicenseAnnotation))
+	})
+
+	t.Run("Only required fields and license annotation from config", func(t *testing.T) {
+		metadata := complete()
+		metadata.Home = ""
+		metadata.Annotations = map[string]string{"example.com/license": "MIT"}
+		require.Empty(t, getMetadataIssues(metadata, []string{LicenseMetadata, VersionMetadata}, "example.com/license"))
+		require.Equal(t, []string{"license : annotation artifacthub.io/license not set"},
+			getMetadataIssues(metadata, []string{LicenseMetadata}, DefaultLicenseAnnotation))
+	})
+}
+
+func TestGetIconReachable(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/icon.png":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte{0x89, 'P', 'N', 'G'})
+		case "/index.html":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html></html>"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	require.NoError(t, getIconReachable(server.URL+"/icon.png"))
+	require.NoError(t, getIconReachable("data:image/png;base64,iVBORw0KGgo="))
+	require.Error(t, getIconReachable(server.URL+"/index.html"))
+	require.Error(t, getIconReachable(server.URL+"/missing.png"))
+	require.Error(t, getIconReachable("data:text/plain;base64,aGVsbG8="))
+	require.Error(t, getIconReachable("ftp://example.com/icon.png"))
+}
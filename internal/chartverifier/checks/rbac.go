@@ -0,0 +1,258 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// clusterScopedKinds are the kinds of the cluster scoped objects a chart commonly creates.
+var clusterScopedKinds = map[string]bool{
+	"APIService":                     true,
+	"ClusterRole":                    true,
+	"ClusterRoleBinding":             true,
+	"CSIDriver":                      true,
+	"CustomResourceDefinition":       true,
+	"IngressClass":                   true,
+	"MutatingWebhookConfiguration":   true,
+	"Namespace":                      true,
+	"PersistentVolume":               true,
+	"PodSecurityPolicy":              true,
+	"PriorityClass":                  true,
+	"RuntimeClass":                   true,
+	"SecurityContextConstraints":     true,
+	"StorageClass":                   true,
+	"ValidatingWebhookConfiguration": true,
+}
+
+// privilegedVerbs are verbs which allow a subject to gain permissions beyond those it was granted.
+var privilegedVerbs = []string{"escalate", "bind", "impersonate"}
+
+// rbacRole is a Role or ClusterRole rendered from the chart.
+type rbacRole struct {
+	Object renderedObject
+	Rules  []rbacv1.PolicyRule
+}
+
+// rbacReport holds the outcome of analysing the RBAC objects rendered from a chart.
+type rbacReport struct {
+	// Findings are the permissions granted by the chart which do not follow least privilege.
+	Findings []string
+	// Permissions are the effective permissions of each service account bound to a role.
+	Permissions []string
+	// ClusterScopedObjects are the cluster scoped objects the chart creates.
+	ClusterScopedObjects []string
+}
+
+// serviceAccountPermissions are the effective permissions of a service account in a scope, the rules of all the roles
+// the bindings of the chart grant it.
+type serviceAccountPermissions struct {
+	ServiceAccount string
+	Scope          string
+	Rules          []rbacv1.PolicyRule
+	// Roles are the roles granting the permissions.
+	Roles        []string
+	ClusterAdmin bool
+}
+
+func (p *serviceAccountPermissions) String() string {
+	return fmt.Sprintf("ServiceAccount %s (%s)", p.ServiceAccount, p.Scope)
+}
+
+// addRole adds the rules of a role to the permissions, merging the rules granting verbs on the same resources.
+func (p *serviceAccountPermissions) addRole(roleName string, role *rbacRole) {
+	if role == nil {
+		roleName = fmt.Sprintf("%s (not created by the chart)", roleName)
+	}
+	if !contains(p.Roles, roleName) {
+		p.Roles = append(p.Roles, roleName)
+	}
+	if role == nil {
+		return
+	}
+	for _, rule := range role.Rules {
+		merged := false
+		for index := range p.Rules {
+			if getRuleTarget(p.Rules[index]) == getRuleTarget(rule) {
+				for _, verb := range rule.Verbs {
+					if !contains(p.Rules[index].Verbs, verb) {
+						p.Rules[index].Verbs = append(p.Rules[index].Verbs, verb)
+					}
+				}
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			p.Rules = append(p.Rules, *rule.DeepCopy())
+		}
+	}
+}
+
+// getRBACReport analyses the Role, ClusterRole, RoleBinding and ClusterRoleBinding objects rendered from a chart.
+// Findings are reported on the effective permissions of each service account the chart binds to roles, and on the
+// roles bound to no service account.
+func getRBACReport(objects []renderedObject) (*rbacReport, error) {
+
+	report := &rbacReport{}
+	roles := make(map[string]*rbacRole)
+	var roleNames []string
+	var bindings []renderedObject
+
+	for _, object := range objects {
+		kind := object.Kind()
+		if clusterScopedKinds[kind] {
+			report.ClusterScopedObjects = append(report.ClusterScopedObjects, object.String())
+		}
+		switch kind {
+		case "Role", "ClusterRole":
+			role := &rbacRole{Object: object}
+			if _, err := object.Decode(&role.Rules, "rules"); err != nil {
+				return nil, err
+			}
+			roleName := fmt.Sprintf("%s/%s", kind, object.Name())
+			roles[roleName] = role
+			roleNames = append(roleNames, roleName)
+		case "RoleBinding", "ClusterRoleBinding":
+			bindings = append(bindings, object)
+		}
+	}
+
+	permissions := make(map[string]*serviceAccountPermissions)
+	boundRoles := make(map[string]bool)
+	for _, binding := range bindings {
+		var roleRef rbacv1.RoleRef
+		var subjects []rbacv1.Subject
+		if _, err := binding.Decode(&roleRef, "roleRef"); err != nil {
+			return nil, err
+		}
+		if _, err := binding.Decode(&subjects, "subjects"); err != nil {
+			return nil, err
+		}
+
+		scope := "namespace"
+		if binding.Kind() == "ClusterRoleBinding" {
+			scope = "cluster-wide"
+		}
+		roleName := fmt.Sprintf("%s/%s", roleRef.Kind, roleRef.Name)
+		role := roles[roleName]
+		clusterAdmin := role == nil && roleRef.Kind == "ClusterRole" && roleRef.Name == "cluster-admin"
+
+		boundToServiceAccount := false
+		for _, subject := range subjects {
+			if subject.Kind != rbacv1.ServiceAccountKind {
+				continue
+			}
+			boundToServiceAccount = true
+			serviceAccount := subject.Name
+			if len(subject.Namespace) > 0 {
+				serviceAccount = fmt.Sprintf("%s/%s", subject.Namespace, subject.Name)
+			}
+			key := fmt.Sprintf("%s (%s)", serviceAccount, scope)
+			accountPermissions, ok := permissions[key]
+			if !ok {
+				accountPermissions = &serviceAccountPermissions{ServiceAccount: serviceAccount, Scope: scope}
+				permissions[key] = accountPermissions
+			}
+			accountPermissions.addRole(roleName, role)
+			accountPermissions.ClusterAdmin = accountPermissions.ClusterAdmin || clusterAdmin
+		}
+		if boundToServiceAccount {
+			boundRoles[roleName] = true
+		} else if clusterAdmin {
+			report.Findings = append(report.Findings, fmt.Sprintf("%s : binds to %s", binding, roleName))
+		}
+	}
+
+	keys := make([]string, 0, len(permissions))
+	for key := range permissions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		accountPermissions := permissions[key]
+		rules := make([]string, 0, len(accountPermissions.Rules))
+		for _, rule := range accountPermissions.Rules {
+			rules = append(rules, describeRule(rule))
+			for _, finding := range getRuleFindings(rule) {
+				report.Findings = append(report.Findings, fmt.Sprintf("%s : %s", accountPermissions, finding))
+			}
+		}
+		if accountPermissions.ClusterAdmin {
+			report.Findings = append(report.Findings, fmt.Sprintf("%s : bound to ClusterRole/cluster-admin", accountPermissions))
+		}
+		if len(rules) == 0 {
+			report.Permissions = append(report.Permissions, fmt.Sprintf("%s : granted by %s",
+				accountPermissions, strings.Join(accountPermissions.Roles, ", ")))
+		} else {
+			report.Permissions = append(report.Permissions, fmt.Sprintf("%s : %s : granted by %s",
+				accountPermissions, strings.Join(rules, "; "), strings.Join(accountPermissions.Roles, ", ")))
+		}
+	}
+
+	// roles bound to users, groups or nothing are still reported, they grant permissions once bound.
+	for _, roleName := range roleNames {
+		if boundRoles[roleName] {
+			continue
+		}
+		role := roles[roleName]
+		for _, rule := range role.Rules {
+			for _, finding := range getRuleFindings(rule) {
+				report.Findings = append(report.Findings, fmt.Sprintf("%s : %s", role.Object, finding))
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// getRuleFindings returns the reasons a policy rule does not follow least privilege.
+func getRuleFindings(rule rbacv1.PolicyRule) []string {
+
+	var findings []string
+	if contains(rule.Verbs, rbacv1.VerbAll) {
+		findings = append(findings, fmt.Sprintf("wildcard verbs : %s", describeRule(rule)))
+	}
+	if contains(rule.APIGroups, rbacv1.APIGroupAll) {
+		findings = append(findings, fmt.Sprintf("wildcard API groups : %s", describeRule(rule)))
+	}
+	if contains(rule.Resources, rbacv1.ResourceAll) {
+		findings = append(findings, fmt.Sprintf("wildcard resources : %s", describeRule(rule)))
+	}
+	for _, verb := range privilegedVerbs {
+		if contains(rule.Verbs, verb) {
+			findings = append(findings, fmt.Sprintf("%s verb : %s", verb, describeRule(rule)))
+		}
+	}
+	if contains(rule.Resources, "secrets") && len(rule.ResourceNames) == 0 {
+		for _, verb := range []string{"get", "list", "watch", rbacv1.VerbAll} {
+			if contains(rule.Verbs, verb) {
+				findings = append(findings, fmt.Sprintf("access to all secrets : %s", describeRule(rule)))
+				break
+			}
+		}
+	}
+	return findings
+}
+
+// getRuleTarget identifies what a policy rule grants verbs on, its API groups, resources, resource names and non
+// resource urls.
+func getRuleTarget(rule rbacv1.PolicyRule) string {
+	return fmt.Sprintf("%s|%s|%s|%s", strings.Join(rule.APIGroups, ","), strings.Join(rule.Resources, ","),
+		strings.Join(rule.ResourceNames, ","), strings.Join(rule.NonResourceURLs, ","))
+}
+
+// describeRule formats a policy rule, for example "get,list,watch pods,services".
+func describeRule(rule rbacv1.PolicyRule) string {
+	targets := rule.Resources
+	if len(rule.NonResourceURLs) > 0 {
+		targets = rule.NonResourceURLs
+	}
+	description := fmt.Sprintf("%s %s", strings.Join(rule.Verbs, ","), strings.Join(targets, ","))
+	if len(rule.ResourceNames) > 0 {
+		description = fmt.Sprintf("%s [%s]", description, strings.Join(rule.ResourceNames, ","))
+	}
+	return description
+}
@@ -0,0 +1,208 @@
+package checks
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+const (
+	// ReadmeSectionsConfigName is the key of the check configuration listing the sections the README requires. Each
+	// section is a list of words separated by "|", a heading containing any one of the words is the section.
+	ReadmeSectionsConfigName = "sections"
+	// RequireParametersConfigName is the key of the check configuration setting if the values must be documented in a
+	// parameters table.
+	RequireParametersConfigName = "requireParameters"
+)
+
+// readmeSections are the sections required when the check configuration does not list them.
+var readmeSections = []string{
+	"install|installing|installation",
+	"configuration|configuring|parameters|values",
+	"uninstall|uninstalling|uninstallation|deleting|removing",
+}
+
+// parameterColumns are the headers of the column of a parameters table holding the parameter names.
+var parameterColumns = []string{"parameter", "name", "key"}
+
+var (
+	atxHeadingRegexp     = regexp.MustCompile(`^ {0,3}#{1,6}(\s+(.*?))?\s*#*\s*$`)
+	setextHeadingRegexp  = regexp.MustCompile(`^ {0,3}(=+|-+)\s*$`)
+	tableDelimiterRegexp = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	fenceRegexp          = regexp.MustCompile("^ {0,3}(```|~~~)")
+	indexRegexp          = regexp.MustCompile(`\[[^\]]*\]`)
+	wordRegexp           = regexp.MustCompile(`[a-z]+`)
+)
+
+// readmeDocument holds the parts of a README used to check it.
+type readmeDocument struct {
+	Headings   []string
+	Parameters []string
+}
+
+// getReadme returns the content of the README of the chart, the name of which is matched ignoring case.
+func getReadme(c *chart.Chart) (string, bool) {
+	for _, f := range c.Files {
+		if strings.EqualFold(f.Name, "README.md") {
+			return string(f.Data), true
+		}
+	}
+	return "", false
+}
+
+func getReadmeSections(opts *CheckOptions) []string {
+	if opts.ViperConfig != nil {
+		if sections := opts.ViperConfig.GetStringSlice(ReadmeSectionsConfigName); len(sections) > 0 {
+			return sections
+		}
+	}
+	return readmeSections
+}
+
+func getRequireParameters(opts *CheckOptions) bool {
+	if opts.ViperConfig != nil && opts.ViperConfig.IsSet(RequireParametersConfigName) {
+		return opts.ViperConfig.GetBool(RequireParametersConfigName)
+	}
+	return true
+}
+
+// parseReadme returns the headings and the documented parameters of a Markdown document. Fenced code blocks are
+// skipped so that shell comments are not read as headings.
+func parseReadme(content string) readmeDocument {
+
+	document := readmeDocument{}
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	inFence := ""
+	for index := 0; index < len(lines); index++ {
+		line := lines[index]
+		if fence := fenceRegexp.FindStringSubmatch(line); fence != nil {
+			if len(inFence) == 0 {
+				inFence = fence[1]
+			} else if inFence == fence[1] {
+				inFence = ""
+			}
+			continue
+		}
+		if len(inFence) > 0 {
+			continue
+		}
+
+		if heading := atxHeadingRegexp.FindStringSubmatch(line); heading != nil {
+			document.Headings = append(document.Headings, heading[2])
+			continue
+		}
+		if index+1 < len(lines) && len(strings.TrimSpace(line)) > 0 && setextHeadingRegexp.MatchString(lines[index+1]) &&
+			!strings.Contains(line, "|") {
+			document.Headings = append(document.Headings, strings.TrimSpace(line))
+			index++
+			continue
+		}
+
+		if strings.Contains(line, "|") && index+1 < len(lines) && tableDelimiterRegexp.MatchString(lines[index+1]) {
+			column := getParameterColumn(splitTableRow(line))
+			index++
+			for index+1 < len(lines) && strings.Contains(lines[index+1], "|") {
+				index++
+				cells := splitTableRow(lines[index])
+				if column >= 0 && column < len(cells) {
+					if parameter := strings.Trim(cells[column], "`* "); len(parameter) > 0 {
+						document.Parameters = append(document.Parameters, parameter)
+					}
+				}
+			}
+		}
+	}
+	return document
+}
+
+func splitTableRow(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	row = strings.TrimSuffix(row, "|")
+	cells := strings.Split(row, "|")
+	for index := range cells {
+		cells[index] = strings.TrimSpace(cells[index])
+	}
+	return cells
+}
+
+// getParameterColumn returns the index of the column holding parameter names, or -1 if the table is not a parameters
+// table.
+func getParameterColumn(headers []string) int {
+	for index, header := range headers {
+		if contains(parameterColumns, strings.ToLower(strings.Trim(header, "`* "))) {
+			return index
+		}
+	}
+	return -1
+}
+
+// getMissingSections returns the required sections which are not a heading of the README.
+func getMissingSections(headings []string, sections []string) []string {
+	missing := make([]string, 0)
+	for _, section := range sections {
+		words := strings.Split(strings.ToLower(section), "|")
+		found := false
+		for _, heading := range headings {
+			for _, word := range wordRegexp.FindAllString(strings.ToLower(heading), -1) {
+				if contains(words, word) {
+					found = true
+					break
+				}
+			}
+			if found {
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, words[0])
+		}
+	}
+	return missing
+}
+
+// getParameterIssues returns the top level keys of the values which are not documented, and the documented parameters
+// which are not in the values.
+func getParameterIssues(values map[string]interface{}, parameters []string) ([]string, []string) {
+
+	documented := make(map[string]bool)
+	stale := make([]string, 0)
+	for _, parameter := range parameters {
+		path := strings.Split(indexRegexp.ReplaceAllString(parameter, ""), ".")
+		documented[path[0]] = true
+		if !hasValuePath(values, path) {
+			stale = append(stale, parameter)
+		}
+	}
+
+	undocumented := make([]string, 0)
+	for key := range values {
+		if !documented[key] {
+			undocumented = append(undocumented, key)
+		}
+	}
+	sort.Strings(undocumented)
+	return undocumented, stale
+}
+
+// hasValuePath checks a path of keys is in the values. A path into a list, or into a value which is not set, cannot be
+// checked so is assumed to be valid.
+func hasValuePath(values map[string]interface{}, path []string) bool {
+	var current interface{} = values
+	for _, key := range path {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[key]
+			if !ok {
+				return false
+			}
+			current = value
+		default:
+			return true
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReadme(t *testing.T) {
+
+	content := `Example
+=======
+
+Installation
+------------
+
+` + "```console" + `
+# a shell comment, not a heading
+$ helm install my-release example/chart
+` + "```" + `
+
+### Configuration ###
+
+| Name | Description |
+|:-----|------------:|
+| **replicaCount** | Number of replicas |
+| ` + "`image.tag`" + ` | Image tag |
+
+| Value | Description |
+|-------|-------------|
+| foo   | Not a parameters table |
+`
+
+	document := parseReadme(content)
+	require.Equal(t, []string{"Example", "Installation", "Configuration"}, document.Headings)
+	require.Equal(t, []string{"replicaCount", "image.tag"}, document.Parameters)
+}
+
+func TestGetMissingSections(t *testing.T) {
+
+	headings := []string{"Introduction", "Installing the Chart", "Parameters"}
+	require.Equal(t, []string{"uninstall"}, getMissingSections(headings, readmeSections))
+	require.Empty(t, getMissingSections(headings, []string{"introduction", "install|installing"}))
+	require.Equal(t, []string{"upgrading"}, getMissingSections(headings, []string{"upgrading|upgrade"}))
+}
+
+func TestGetParameterIssues(t *testing.T) {
+
+	values := map[string]interface{}{
+		"replicaCount": 1,
+		"image":        map[string]interface{}{"repository": "example", "tag": "1.0.0"},
+		"tolerations":  []interface{}{map[string]interface{}{"key": "example"}},
+		"nodeSelector": nil,
+		"resources":    map[string]interface{}{},
+	}
+	parameters := []string{"replicaCount", "image.repository", "image.pullPolicy", "tolerations[0].key",
+		"nodeSelector.zone", "service.port"}
+
+	undocumented, stale := getParameterIssues(values, parameters)
+	require.Equal(t, []string{"resources"}, undocumented)
+	require.Equal(t, []string{"image.pullPolicy", "service.port"}, stale)
+}
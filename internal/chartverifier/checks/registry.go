@@ -17,6 +17,7 @@
 package checks
 
 import (
+	"fmt"
 	"time"
 
 	apiChecks "github.com/redhat-certification/chart-verifier/pkg/chartverifier/checks"
@@ -32,6 +33,20 @@ type Result struct {
 	// Reason for the result value.  This is a message indicating
 	// the reason for the value of Ok became true or false.
 	Reason string
+	// Subcharts contains the result of each check run on each subchart, for a check which runs the checks of the
+	// profile on subcharts.
+	Subcharts []SubchartResult
+	// ClusterUnavailable indicates the check failed because its cluster could not be reached, rather than because
+	// of the chart.
+	ClusterUnavailable bool
+}
+
+// SubchartResult is the result of a check of the profile run on a subchart.
+type SubchartResult struct {
+	// Subchart is the path of the subchart in the chart, for example charts/postgresql.
+	Subchart string
+	Check    Check
+	Result   Result
 }
 
 func NewResult(outcome bool, reason string) Result {
@@ -42,6 +57,13 @@ func NewResult(outcome bool, reason string) Result {
 	return result
 }
 
+// NewClusterUnavailableResult returns the result of a check which failed because its cluster could not be reached.
+func NewClusterUnavailableResult(err error) Result {
+	result := NewResult(false, fmt.Sprintf("%s : %v", ClusterUnavailable, err))
+	result.ClusterUnavailable = true
+	return result
+}
+
 func NewSkippedResult(reason string) Result {
 	result := Result{}
 	result.Ok = true
@@ -79,8 +101,13 @@ func (r *Result) AddResult(outcome bool, reason string) Result {
 
 type AnnotationHolder interface {
 	SetCertifiedOpenShiftVersion(version string)
+	// SetEmulatedOpenShiftVersion records the OpenShift version emulated by the cluster the chart was tested on, which
+	// is reported as tested but not as certified.
+	SetEmulatedOpenShiftVersion(version string)
 	GetCertifiedOpenShiftVersionFlag() string
 	SetSupportedOpenShiftVersions(versions string)
+	// SetTestedCluster records the distribution, platform and exact version of the cluster the chart was tested on.
+	SetTestedCluster(distribution, platform, version string)
 }
 
 type CheckId struct {
@@ -91,6 +118,8 @@ type Check struct {
 	CheckId CheckId
 	Type    apiChecks.CheckType
 	Func    CheckFunc
+	// Config holds the profile's default settings for the check.
+	Config map[string]interface{}
 }
 
 // CheckOptions contains options collected from the environment a check can
@@ -112,6 +141,12 @@ type CheckOptions struct {
 	PublicKeys []string
 	// helm install timeout
 	HelmInstallTimeout time.Duration
+	// KeepOnFailure keeps a release which failed chart testing installed for debugging.
+	KeepOnFailure bool
+	// EphemeralCluster runs chart testing on a throwaway local cluster rather than the cluster of the kubeconfig.
+	EphemeralCluster bool
+	// ProfileChecks contains the checks of the profile in use, for checks which run other checks on subcharts.
+	ProfileChecks []Check
 }
 
 type CheckFunc func(options *CheckOptions) (Result, error)
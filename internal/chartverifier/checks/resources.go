@@ -0,0 +1,115 @@
+package checks
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// MaxLimitRequestRatioConfigName is the key of the check configuration setting the highest allowed ratio of a
+	// container's resource limit to its resource request; a ratio of 0 disables the ratio check.
+	MaxLimitRequestRatioConfigName = "maxLimitRequestRatio"
+	// RequireProbesConfigName is the key of the check configuration setting if containers require probes.
+	RequireProbesConfigName = "requireProbes"
+
+	DefaultMaxLimitRequestRatio = 4.0
+)
+
+// resourceThresholds are the thresholds containers are checked against.
+type resourceThresholds struct {
+	MaxLimitRequestRatio float64
+	RequireProbes        bool
+}
+
+// containerResourceReport holds the resource and probe issues found for a container of a workload.
+type containerResourceReport struct {
+	Object    renderedObject
+	Container string
+	Issues    []string
+}
+
+func getResourceThresholds(opts *CheckOptions) resourceThresholds {
+	thresholds := resourceThresholds{MaxLimitRequestRatio: DefaultMaxLimitRequestRatio, RequireProbes: true}
+	if opts.ViperConfig != nil {
+		if opts.ViperConfig.IsSet(MaxLimitRequestRatioConfigName) {
+			thresholds.MaxLimitRequestRatio = opts.ViperConfig.GetFloat64(MaxLimitRequestRatioConfigName)
+		}
+		if opts.ViperConfig.IsSet(RequireProbesConfigName) {
+			thresholds.RequireProbes = opts.ViperConfig.GetBool(RequireProbesConfigName)
+		}
+	}
+	return thresholds
+}
+
+// getResourceReports returns a report for each container of the rendered workloads with missing resource requests or
+// limits, limits too far above requests, or missing probes.
+func getResourceReports(objects []renderedObject, thresholds resourceThresholds) ([]*containerResourceReport, error) {
+
+	reports := make([]*containerResourceReport, 0)
+	for _, object := range objects {
+		podSpec, err := getPodSpec(object)
+		if err != nil {
+			return nil, err
+		}
+		if podSpec == nil {
+			continue
+		}
+
+		// Pods, Jobs and CronJobs, such as tests and hooks, run to completion so are not expected to have probes.
+		requireProbes := thresholds.RequireProbes
+		switch object.Kind() {
+		case "Pod", "Job", "CronJob":
+			requireProbes = false
+		}
+
+		for _, container := range podSpec.InitContainers {
+			report := getContainerResourceReport(object, container, thresholds, false)
+			if len(report.Issues) > 0 {
+				reports = append(reports, report)
+			}
+		}
+		for _, container := range podSpec.Containers {
+			report := getContainerResourceReport(object, container, thresholds, requireProbes)
+			if len(report.Issues) > 0 {
+				reports = append(reports, report)
+			}
+		}
+	}
+	return reports, nil
+}
+
+func getContainerResourceReport(object renderedObject, container corev1.Container, thresholds resourceThresholds, requireProbes bool) *containerResourceReport {
+
+	report := &containerResourceReport{Object: object, Container: container.Name}
+	resources := container.Resources
+
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		request, hasRequest := resources.Requests[name]
+		limit, hasLimit := resources.Limits[name]
+		if !hasRequest {
+			report.Issues = append(report.Issues, fmt.Sprintf("missing %s request", name))
+		}
+		if !hasLimit {
+			report.Issues = append(report.Issues, fmt.Sprintf("missing %s limit", name))
+		}
+		if hasRequest && hasLimit && thresholds.MaxLimitRequestRatio > 0 && !request.IsZero() {
+			ratio := limit.AsApproximateFloat64() / request.AsApproximateFloat64()
+			if ratio > thresholds.MaxLimitRequestRatio {
+				report.Issues = append(report.Issues, fmt.Sprintf("%s limit to request ratio %.1f exceeds %.1f",
+					name, ratio, thresholds.MaxLimitRequestRatio))
+			}
+		}
+	}
+
+	if requireProbes {
+		if container.ReadinessProbe == nil {
+			report.Issues = append(report.Issues, "missing readiness probe")
+		}
+		if container.LivenessProbe == nil {
+			report.Issues = append(report.Issues, "missing liveness probe")
+		}
+	}
+
+	return report
+}
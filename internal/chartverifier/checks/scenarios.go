@@ -0,0 +1,80 @@
+package checks
+
+import (
+	"fmt"
+	"path"
+	"sync"
+)
+
+// scenarioResult is the outcome of installing and testing a release of the chart with one of its values files.
+type scenarioResult struct {
+	// ValuesFile is the values file of the scenario, empty when the chart is tested with its default values.
+	ValuesFile string
+	Error      error
+}
+
+// String summarises the scenario result for the chart-testing result.
+func (r scenarioResult) String() string {
+	name := "default values"
+	if len(r.ValuesFile) > 0 {
+		name = path.Base(r.ValuesFile)
+	}
+	if r.Error != nil {
+		return fmt.Sprintf("%s %s : failed : %v", ChartTestScenario, name, r.Error)
+	}
+	return fmt.Sprintf("%s %s : passed", ChartTestScenario, name)
+}
+
+// runScenarios runs the scenario of each values file, up to concurrency scenarios at a time, and returns the result of
+// each scenario run in the order of the values files. With a concurrency of one or less the scenarios are run one
+// after the other, and the first failure stops the run.
+func runScenarios(valuesFiles []string, concurrency int, run func(valuesFile string) error) []scenarioResult {
+	if concurrency <= 1 {
+		results := make([]scenarioResult, 0, len(valuesFiles))
+		for _, valuesFile := range valuesFiles {
+			err := run(valuesFile)
+			results = append(results, scenarioResult{ValuesFile: valuesFile, Error: err})
+			if err != nil {
+				break
+			}
+		}
+		return results
+	}
+
+	results := make([]scenarioResult, len(valuesFiles))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for index, valuesFile := range valuesFiles {
+		wg.Add(1)
+		go func(index int, valuesFile string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			results[index] = scenarioResult{ValuesFile: valuesFile, Error: run(valuesFile)}
+		}(index, valuesFile)
+	}
+	wg.Wait()
+	return results
+}
+
+// getScenariosError returns the error of the first failed scenario.
+func getScenariosError(scenarios []scenarioResult) error {
+	for _, scenario := range scenarios {
+		if scenario.Error != nil {
+			return scenario.Error
+		}
+	}
+	return nil
+}
+
+// addScenarioResults adds the outcome of each scenario to the reason of the chart-testing result when more than one
+// scenario was run, without changing the outcome of the result.
+func addScenarioResults(r Result, scenarios []scenarioResult) Result {
+	if len(scenarios) <= 1 {
+		return r
+	}
+	for _, scenario := range scenarios {
+		r.AddResult(r.Ok, scenario.String())
+	}
+	return r
+}
@@ -0,0 +1,106 @@
+package checks
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/cli"
+
+	"github.com/redhat-certification/chart-verifier/internal/tool"
+)
+
+func TestRunScenarios(t *testing.T) {
+
+	valuesFiles := []string{"ci/a-values.yaml", "ci/b-values.yaml", "ci/c-values.yaml", "ci/d-values.yaml"}
+	failB := func(valuesFile string) error {
+		if valuesFile == "ci/b-values.yaml" {
+			return errors.New("b failed")
+		}
+		return nil
+	}
+
+	t.Run("Sequential run stops at the first failure", func(t *testing.T) {
+		scenarios := runScenarios(valuesFiles, 1, failB)
+		require.Len(t, scenarios, 2)
+		require.NoError(t, scenarios[0].Error)
+		require.EqualError(t, scenarios[1].Error, "b failed")
+		require.EqualError(t, getScenariosError(scenarios), "b failed")
+	})
+
+	t.Run("Concurrent run reports every scenario in order", func(t *testing.T) {
+		scenarios := runScenarios(valuesFiles, 3, failB)
+		require.Len(t, scenarios, 4)
+		for index, scenario := range scenarios {
+			require.Equal(t, valuesFiles[index], scenario.ValuesFile)
+		}
+		require.NoError(t, scenarios[0].Error)
+		require.EqualError(t, scenarios[1].Error, "b failed")
+		require.NoError(t, scenarios[2].Error)
+		require.NoError(t, scenarios[3].Error)
+	})
+
+	t.Run("Concurrent run is capped", func(t *testing.T) {
+		var mutex sync.Mutex
+		running, maxRunning := 0, 0
+		run := func(valuesFile string) error {
+			mutex.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mutex.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+			return nil
+		}
+		scenarios := runScenarios(valuesFiles, 2, run)
+		require.Len(t, scenarios, 4)
+		require.NoError(t, getScenariosError(scenarios))
+		require.Equal(t, 2, maxRunning)
+	})
+}
+
+func TestAddScenarioResults(t *testing.T) {
+
+	r := addScenarioResults(NewResult(true, ChartTestingSuccess), []scenarioResult{{}})
+	require.Equal(t, ChartTestingSuccess, r.Reason)
+
+	scenarios := []scenarioResult{
+		{ValuesFile: "/tmp/chart/ci/a-values.yaml"},
+		{ValuesFile: "/tmp/chart/ci/b-values.yaml", Error: errors.New("Chart test failure: timeout")},
+	}
+	r = addScenarioResults(NewResult(false, "Chart test failure: timeout"), scenarios)
+	require.False(t, r.Ok)
+	require.Equal(t, "Chart test failure: timeout\nValues scenario a-values.yaml : passed\nValues scenario b-values.yaml : failed : Chart test failure: timeout", r.Reason)
+}
+
+func TestGetConcurrency(t *testing.T) {
+
+	config := viper.New()
+	opts := &CheckOptions{ViperConfig: config}
+	require.Equal(t, 0, getConcurrency(opts, ""))
+
+	config.Set(ConcurrencyConfigName, 4)
+	require.Equal(t, 4, getConcurrency(opts, ""))
+	require.Equal(t, 1, getConcurrency(opts, "my-release"))
+}
+
+func TestGetScenarioHelm(t *testing.T) {
+
+	helm, err := tool.NewHelm(cli.New(), nil, 0)
+	require.NoError(t, err)
+
+	scenarioHelm, err := getScenarioHelm(helm, 1)
+	require.NoError(t, err)
+	require.Same(t, helm, scenarioHelm)
+
+	scenarioHelm, err = getScenarioHelm(helm, 2)
+	require.NoError(t, err)
+	require.NotSame(t, helm, scenarioHelm)
+}
@@ -0,0 +1,186 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// securityFinding is a setting in a workload's pod spec which is not allowed by the restricted-v2 SCC.
+type securityFinding string
+
+const (
+	privilegedFinding      securityFinding = "privileged"
+	hostNetworkFinding     securityFinding = "hostNetwork"
+	hostPIDFinding         securityFinding = "hostPID"
+	hostPathFinding        securityFinding = "hostPath"
+	runAsRootFinding       securityFinding = "runAsRoot"
+	runAsFixedUserFinding  securityFinding = "runAsFixedUser"
+	addCapabilitiesFinding securityFinding = "addCapabilities"
+)
+
+// securityContextConstraint is an OpenShift SCC together with the findings it allows.
+type securityContextConstraint struct {
+	Name   string
+	Allows []securityFinding
+}
+
+// sccs lists the default OpenShift SCCs, from the most to the least restrictive. A workload requires the first SCC
+// which allows all of its findings.
+var sccs = []securityContextConstraint{
+	{Name: "restricted-v2"},
+	{Name: "nonroot-v2", Allows: []securityFinding{runAsFixedUserFinding}},
+	{Name: "anyuid", Allows: []securityFinding{runAsFixedUserFinding, runAsRootFinding}},
+	{Name: "hostnetwork-v2", Allows: []securityFinding{hostNetworkFinding}},
+	{Name: "hostmount-anyuid", Allows: []securityFinding{hostPathFinding, runAsFixedUserFinding, runAsRootFinding}},
+	{Name: "hostaccess", Allows: []securityFinding{hostNetworkFinding, hostPIDFinding, hostPathFinding}},
+	{Name: "privileged", Allows: []securityFinding{privilegedFinding, hostNetworkFinding, hostPIDFinding, hostPathFinding,
+		runAsRootFinding, runAsFixedUserFinding, addCapabilitiesFinding}},
+}
+
+// restrictedCapabilities are the capabilities the restricted-v2 SCC allows a container to add.
+var restrictedCapabilities = []corev1.Capability{"NET_BIND_SERVICE"}
+
+func (scc securityContextConstraint) allows(finding securityFinding) bool {
+	for _, allowed := range scc.Allows {
+		if allowed == finding {
+			return true
+		}
+	}
+	return false
+}
+
+// workloadSecurityReport holds the settings of a workload which are not allowed by the restricted-v2 SCC.
+type workloadSecurityReport struct {
+	Object   renderedObject
+	Findings []securityFinding
+	Details  []string
+}
+
+func (w *workloadSecurityReport) add(finding securityFinding, detail string) {
+	w.Findings = append(w.Findings, finding)
+	w.Details = append(w.Details, detail)
+}
+
+// RequiredSCC returns the name of the least permissive SCC the workload can be admitted with.
+func (w *workloadSecurityReport) RequiredSCC() string {
+	for _, scc := range sccs {
+		allowed := true
+		for _, finding := range w.Findings {
+			if !scc.allows(finding) {
+				allowed = false
+				break
+			}
+		}
+		if allowed {
+			return scc.Name
+		}
+	}
+	return sccs[len(sccs)-1].Name
+}
+
+// getPodSpec returns the pod spec of a workload, or nil if the object is not a workload.
+func getPodSpec(object renderedObject) (*corev1.PodSpec, error) {
+
+	var specPath []string
+	switch object.Kind() {
+	case "Pod":
+		specPath = []string{"spec"}
+	case "CronJob":
+		specPath = []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	case "Deployment", "DeploymentConfig", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController", "Job":
+		specPath = []string{"spec", "template", "spec"}
+	default:
+		return nil, nil
+	}
+
+	podSpec := &corev1.PodSpec{}
+	found, err := object.Decode(podSpec, specPath...)
+	if err != nil || !found {
+		return nil, err
+	}
+	return podSpec, nil
+}
+
+// getWorkloadSecurityReport checks the pod spec of a workload against the restricted-v2 SCC.
+func getWorkloadSecurityReport(object renderedObject, podSpec *corev1.PodSpec) *workloadSecurityReport {
+
+	report := &workloadSecurityReport{Object: object}
+
+	if podSpec.HostNetwork {
+		report.add(hostNetworkFinding, "uses the host network")
+	}
+	if podSpec.HostPID {
+		report.add(hostPIDFinding, "uses the host PID namespace")
+	}
+	for _, volume := range podSpec.Volumes {
+		if volume.HostPath != nil {
+			report.add(hostPathFinding, fmt.Sprintf("mounts host path %s in volume %s", volume.HostPath.Path, volume.Name))
+		}
+	}
+
+	var podRunAsUser *int64
+	if podSpec.SecurityContext != nil {
+		podRunAsUser = podSpec.SecurityContext.RunAsUser
+	}
+
+	containers := append(append([]corev1.Container{}, podSpec.InitContainers...), podSpec.Containers...)
+	for _, container := range containers {
+		runAsUser := podRunAsUser
+		if sc := container.SecurityContext; sc != nil {
+			if sc.Privileged != nil && *sc.Privileged {
+				report.add(privilegedFinding, fmt.Sprintf("container %s is privileged", container.Name))
+			}
+			if sc.RunAsUser != nil {
+				runAsUser = sc.RunAsUser
+			}
+			if sc.Capabilities != nil {
+				for _, capability := range sc.Capabilities.Add {
+					if !isRestrictedCapability(capability) {
+						report.add(addCapabilitiesFinding, fmt.Sprintf("container %s adds capability %s", container.Name, capability))
+					}
+				}
+			}
+		}
+		if runAsUser != nil {
+			if *runAsUser == 0 {
+				report.add(runAsRootFinding, fmt.Sprintf("container %s runs as user 0", container.Name))
+			} else {
+				report.add(runAsFixedUserFinding, fmt.Sprintf("container %s runs as fixed user %d", container.Name, *runAsUser))
+			}
+		}
+	}
+
+	return report
+}
+
+func isRestrictedCapability(capability corev1.Capability) bool {
+	for _, allowed := range restrictedCapabilities {
+		if strings.EqualFold(string(allowed), string(capability)) {
+			return true
+		}
+	}
+	return false
+}
+
+// getSecurityReports returns a report for each workload in the rendered objects which is not allowed by the
+// restricted-v2 SCC.
+func getSecurityReports(objects []renderedObject) ([]*workloadSecurityReport, error) {
+
+	reports := make([]*workloadSecurityReport, 0)
+	for _, object := range objects {
+		podSpec, err := getPodSpec(object)
+		if err != nil {
+			return nil, err
+		}
+		if podSpec == nil {
+			continue
+		}
+		report := getWorkloadSecurityReport(object, podSpec)
+		if len(report.Findings) > 0 {
+			reports = append(reports, report)
+		}
+	}
+	return reports, nil
+}
@@ -0,0 +1,107 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/helm/chart-testing/v3/pkg/chart"
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier/utils"
+	"github.com/redhat-certification/chart-verifier/internal/tool"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// testHookOutputLines is the number of lines of the output of a test hook kept in the chart-testing result.
+const testHookOutputLines = 5
+
+// testHookResult is the outcome of the last run of a helm test hook.
+type testHookResult struct {
+	Name     string
+	Phase    string
+	Duration time.Duration
+	// Output holds the last lines of the logs of the test hook pod.
+	Output []string
+}
+
+// String summarises the test hook result for the chart-testing result, its last lines of output indented below it.
+func (r testHookResult) String() string {
+	summary := fmt.Sprintf("%s %s : %s in %s", ChartTestHook, r.Name, r.Phase, r.Duration)
+	for _, line := range r.Output {
+		summary += "\n    " + line
+	}
+	return summary
+}
+
+// releaseTestResult is the result of testing the releases of a chart, with the outcome of the test hooks of the
+// version of the chart being verified and what their cleanup left over.
+type releaseTestResult struct {
+	chart.TestResult
+	TestHooks []testHookResult
+	// CleanupLeftovers are the objects left over by the cleanup of the releases.
+	CleanupLeftovers []string
+	// Scenarios are the outcomes of testing a release with each values file.
+	Scenarios []scenarioResult
+}
+
+// newTestHookResult builds the result of a test hook from its last run and the logs of its pod.
+func newTestHookResult(hook *release.Hook, logs string) testHookResult {
+	result := testHookResult{Name: hook.Name, Phase: string(hook.LastRun.Phase)}
+	if !hook.LastRun.StartedAt.IsZero() && !hook.LastRun.CompletedAt.IsZero() {
+		result.Duration = hook.LastRun.CompletedAt.Sub(hook.LastRun.StartedAt).Round(time.Second)
+	}
+	lines := strings.Split(strings.TrimRight(logs, "\n"), "\n")
+	if len(lines) > testHookOutputLines {
+		lines = lines[len(lines)-testHookOutputLines:]
+	}
+	for _, line := range lines {
+		if len(strings.TrimSpace(line)) > 0 {
+			result.Output = append(result.Output, line)
+		}
+	}
+	return result
+}
+
+// addTestHookResults adds the summary of each test hook to the reason of the chart-testing result, the test hooks not
+// changing the outcome of the result.
+func addTestHookResults(r Result, testHooks []testHookResult) Result {
+	for _, testHook := range testHooks {
+		r.AddResult(r.Ok, testHook.String())
+	}
+	return r
+}
+
+// isTestHook returns whether the hook is a helm test pod.
+func isTestHook(hook *release.Hook) bool {
+	if hook.Kind != "Pod" {
+		return false
+	}
+	for _, event := range hook.Events {
+		if event == release.HookTest {
+			return true
+		}
+	}
+	return false
+}
+
+// getTestHookResults fetches the logs of the test hook pods of a tested release, writes them to the verifier log and
+// returns the outcome of each test hook which has run.
+func getTestHookResults(ctx context.Context, kubectl *tool.Kubectl, namespace string, rel *release.Release) []testHookResult {
+	results := make([]testHookResult, 0)
+	if rel == nil {
+		return results
+	}
+	for _, hook := range rel.Hooks {
+		if !isTestHook(hook) || hook.LastRun.Phase == release.HookPhaseUnknown {
+			continue
+		}
+		logs, err := kubectl.GetPodLogs(ctx, namespace, hook.Name)
+		if err != nil {
+			utils.LogWarning(fmt.Sprintf("Error getting logs of test hook %s: %v", hook.Name, err))
+		} else {
+			utils.LogInfo(fmt.Sprintf("Test hook %s %s, output:\n%s", hook.Name, hook.LastRun.Phase, logs))
+		}
+		results = append(results, newTestHookResult(hook, logs))
+	}
+	return results
+}
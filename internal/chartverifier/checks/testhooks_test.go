@@ -0,0 +1,53 @@
+package checks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/release"
+	helmtime "helm.sh/helm/v3/pkg/time"
+)
+
+func TestNewTestHookResult(t *testing.T) {
+
+	started := helmtime.Now()
+	hook := &release.Hook{
+		Name: "chart-test-connection",
+		LastRun: release.HookExecution{
+			StartedAt:   started,
+			CompletedAt: started.Add(3 * time.Second),
+			Phase:       release.HookPhaseFailed,
+		},
+	}
+
+	result := newTestHookResult(hook, "line 1\nline 2\n\nline 3\nline 4\nline 5\nline 6\n")
+	require.Equal(t, "chart-test-connection", result.Name)
+	require.Equal(t, "Failed", result.Phase)
+	require.Equal(t, 3*time.Second, result.Duration)
+	require.Equal(t, []string{"line 3", "line 4", "line 5", "line 6"}, result.Output)
+	require.Equal(t, "Test hook chart-test-connection : Failed in 3s\n    line 3\n    line 4\n    line 5\n    line 6", result.String())
+
+	result = newTestHookResult(&release.Hook{Name: "no-output", LastRun: release.HookExecution{Phase: release.HookPhaseSucceeded}}, "")
+	require.Empty(t, result.Output)
+	require.Equal(t, "Test hook no-output : Succeeded in 0s", result.String())
+}
+
+func TestIsTestHook(t *testing.T) {
+
+	require.True(t, isTestHook(&release.Hook{Kind: "Pod", Events: []release.HookEvent{release.HookTest}}))
+	require.False(t, isTestHook(&release.Hook{Kind: "Pod", Events: []release.HookEvent{release.HookPreInstall}}))
+	require.False(t, isTestHook(&release.Hook{Kind: "ConfigMap", Events: []release.HookEvent{release.HookTest}}))
+}
+
+func TestAddTestHookResults(t *testing.T) {
+
+	testHooks := []testHookResult{{Name: "first", Phase: "Succeeded", Duration: time.Second}, {Name: "second", Phase: "Failed", Duration: 2 * time.Second}}
+
+	r := addTestHookResults(NewResult(true, ChartTestingSuccess), testHooks)
+	require.True(t, r.Ok)
+	require.Equal(t, ChartTestingSuccess+"\nTest hook first : Succeeded in 1s\nTest hook second : Failed in 2s", r.Reason)
+
+	r = addTestHookResults(NewResult(false, "Chart test failure"), testHooks)
+	require.False(t, r.Ok)
+}
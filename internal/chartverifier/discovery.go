@@ -0,0 +1,105 @@
+package chartverifier
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/repo"
+	"sigs.k8s.io/yaml"
+
+	"github.com/redhat-certification/chart-verifier/internal/tool"
+)
+
+const indexFileName = "index.yaml"
+
+// DiscoverCharts returns the uri of each chart of a directory tree, or of the latest version of each chart of a chart
+// repository index file or url.
+func DiscoverCharts(source string) ([]string, error) {
+	if path.Base(source) == indexFileName {
+		return discoverIndexCharts(source)
+	}
+	if info, err := os.Stat(source); err != nil {
+		return nil, fmt.Errorf("error reading charts from %s: %v", source, err)
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("%s is neither a directory nor a chart repository %s", source, indexFileName)
+	}
+	return discoverDirectoryCharts(source)
+}
+
+// discoverDirectoryCharts returns the directories holding a Chart.yaml, and the chart archives, of a directory tree.
+// The subcharts of a chart and hidden directories are not searched.
+func discoverDirectoryCharts(dir string) ([]string, error) {
+	charts := make([]string, 0)
+	err := filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if filePath != dir && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(filePath, chartutil.ChartfileName)); err == nil {
+				charts = append(charts, filePath)
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(entry.Name(), ".tgz") {
+			charts = append(charts, filePath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading charts from %s: %v", dir, err)
+	}
+	sort.Strings(charts)
+	return charts, nil
+}
+
+// discoverIndexCharts returns the url of the latest version of each chart of a chart repository index, urls relative
+// to the repository being resolved against the location of the index.
+func discoverIndexCharts(indexURI string) ([]string, error) {
+	content, err := tool.ReadURI(indexURI)
+	if err != nil {
+		return nil, fmt.Errorf("error reading chart repository index %s: %v", indexURI, err)
+	}
+	index := repo.NewIndexFile()
+	if err = yaml.Unmarshal(content, index); err != nil {
+		return nil, fmt.Errorf("error reading chart repository index %s: %v", indexURI, err)
+	}
+	index.SortEntries()
+
+	var baseURL string
+	if u, err := url.Parse(indexURI); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		baseURL = strings.TrimSuffix(indexURI, indexFileName)
+	} else if baseURL, err = filepath.Abs(filepath.Dir(indexURI)); err != nil {
+		return nil, fmt.Errorf("error reading chart repository index %s: %v", indexURI, err)
+	}
+
+	names := make([]string, 0, len(index.Entries))
+	for name := range index.Entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	charts := make([]string, 0, len(names))
+	for _, name := range names {
+		versions := index.Entries[name]
+		if len(versions) == 0 || len(versions[0].URLs) == 0 {
+			continue
+		}
+		chartURL, err := repo.ResolveReferenceURL(baseURL, versions[0].URLs[0])
+		if err != nil {
+			return nil, fmt.Errorf("error resolving the url of chart %s %s: %v", name, versions[0].Version, err)
+		}
+		charts = append(charts, chartURL)
+	}
+	return charts, nil
+}
@@ -0,0 +1,92 @@
+package chartverifier
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testIndex = `apiVersion: v1
+entries:
+  beta:
+    - name: beta
+      version: 1.0.0
+      apiVersion: v2
+      urls:
+        - https://charts.example.com/beta-1.0.0.tgz
+  alpha:
+    - name: alpha
+      version: 0.1.0
+      apiVersion: v2
+      urls:
+        - charts/alpha-0.1.0.tgz
+    - name: alpha
+      version: 0.2.0
+      apiVersion: v2
+      urls:
+        - charts/alpha-0.2.0.tgz
+`
+
+func writeTestFile(t *testing.T, filePath string, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+	require.NoError(t, ioutil.WriteFile(filePath, []byte(content), 0644))
+}
+
+func TestDiscoverCharts(t *testing.T) {
+
+	t.Run("Directory tree", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestFile(t, filepath.Join(dir, "charts", "alpha", "Chart.yaml"), "name: alpha\n")
+		writeTestFile(t, filepath.Join(dir, "charts", "alpha", "charts", "sub", "Chart.yaml"), "name: sub\n")
+		writeTestFile(t, filepath.Join(dir, "charts", "beta", "Chart.yaml"), "name: beta\n")
+		writeTestFile(t, filepath.Join(dir, "packages", "gamma-0.1.0.tgz"), "")
+		writeTestFile(t, filepath.Join(dir, ".git", "delta", "Chart.yaml"), "name: delta\n")
+		writeTestFile(t, filepath.Join(dir, "README.md"), "")
+
+		charts, err := DiscoverCharts(dir)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			filepath.Join(dir, "charts", "alpha"),
+			filepath.Join(dir, "charts", "beta"),
+			filepath.Join(dir, "packages", "gamma-0.1.0.tgz"),
+		}, charts)
+	})
+
+	t.Run("Index file", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestFile(t, filepath.Join(dir, "index.yaml"), testIndex)
+
+		charts, err := DiscoverCharts(filepath.Join(dir, "index.yaml"))
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			filepath.Join(dir, "charts", "alpha-0.2.0.tgz"),
+			"https://charts.example.com/beta-1.0.0.tgz",
+		}, charts)
+	})
+
+	t.Run("Index url", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(testIndex))
+		}))
+		defer server.Close()
+
+		charts, err := DiscoverCharts(server.URL + "/repo/index.yaml")
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			server.URL + "/repo/charts/alpha-0.2.0.tgz",
+			"https://charts.example.com/beta-1.0.0.tgz",
+		}, charts)
+	})
+
+	t.Run("Not a directory", func(t *testing.T) {
+		_, err := DiscoverCharts("checks/chart-0.1.0-v3.valid.tgz")
+		require.Error(t, err)
+		_, err = DiscoverCharts("does-not-exist")
+		require.Error(t, err)
+	})
+}
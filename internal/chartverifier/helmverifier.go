@@ -37,6 +37,10 @@ type VerifierBuilder interface {
 	SetTimeout(time.Duration) VerifierBuilder
 	SetPublicKeys([]string) VerifierBuilder
 	SetHelmInstallTimeout(time.Duration) VerifierBuilder
+	SetKeepOnFailure(bool) VerifierBuilder
+	SetEphemeralCluster(bool) VerifierBuilder
+	SetVersionMap(string) VerifierBuilder
+	SetBaseline(string) VerifierBuilder
 	SetSettings(settings *cli.EnvSettings) VerifierBuilder
 	Build() (Verifier, error)
 }
@@ -3,6 +3,7 @@ package profiles
 import (
 	"fmt"
 
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier/checks"
 	apiChecks "github.com/redhat-certification/chart-verifier/pkg/chartverifier/checks"
 )
 
@@ -10,7 +11,7 @@ const (
 	CheckVersion10        = "v1.0"
 	CheckVersion11        = "v1.1"
 	DefaultProfile        = "partner"
-	DefaultProfileVersion = "v1.2"
+	DefaultProfileVersion = "v1.3"
 )
 
 func getDefaultProfile(msg string) *Profile {
@@ -41,8 +42,17 @@ func getDefaultProfile(msg string) *Profile {
 		{Name: fmt.Sprintf("%s/%s", CheckVersion10, apiChecks.NotContainCsiObjects), Type: apiChecks.MandatoryCheckType},
 		{Name: fmt.Sprintf("%s/%s", CheckVersion11, apiChecks.ImagesAreCertified), Type: apiChecks.MandatoryCheckType},
 		{Name: fmt.Sprintf("%s/%s", CheckVersion10, apiChecks.ChartTesting), Type: apiChecks.MandatoryCheckType},
-		{Name: fmt.Sprintf("%s/%s", CheckVersion10, apiChecks.RequiredAnnotationsPresent), Type: apiChecks.MandatoryCheckType},
+		{Name: fmt.Sprintf("%s/%s", CheckVersion10, apiChecks.RequiredAnnotationsPresent), Type: apiChecks.MandatoryCheckType,
+			Config: map[string]interface{}{checks.RequiredAnnotationsConfigName: []interface{}{"charts.openshift.io/name"}}},
 		{Name: fmt.Sprintf("%s/%s", CheckVersion10, apiChecks.SignatureIsValid), Type: apiChecks.MandatoryCheckType},
+		{Name: fmt.Sprintf("%s/%s", CheckVersion10, apiChecks.RunsWithRestrictedSCC), Type: apiChecks.OptionalCheckType},
+		{Name: fmt.Sprintf("%s/%s", CheckVersion10, apiChecks.HasLeastPrivilegeRBAC), Type: apiChecks.OptionalCheckType},
+		{Name: fmt.Sprintf("%s/%s", CheckVersion10, apiChecks.KeywordsAreOpenshiftCategories), Type: apiChecks.OptionalCheckType},
+		{Name: fmt.Sprintf("%s/%s", CheckVersion10, apiChecks.IsCommercialChart), Type: apiChecks.OptionalCheckType},
+		{Name: fmt.Sprintf("%s/%s", CheckVersion10, apiChecks.HasResourceRequirements), Type: apiChecks.OptionalCheckType},
+		{Name: fmt.Sprintf("%s/%s", CheckVersion10, apiChecks.DependenciesAreValid), Type: apiChecks.OptionalCheckType},
+		{Name: fmt.Sprintf("%s/%s", CheckVersion10, apiChecks.HasCompleteMetadata), Type: apiChecks.OptionalCheckType},
+		{Name: fmt.Sprintf("%s/%s", CheckVersion10, apiChecks.HasCompleteReadme), Type: apiChecks.OptionalCheckType},
 	}
 
 	return &profile
@@ -10,6 +10,7 @@ import (
 	"gopkg.in/yaml.v3"
 	"regexp"
 	"strings"
+	"sync"
 )
 
 type Annotation string
@@ -28,6 +29,8 @@ const (
 
 	VendorTypeDefault      VendorType = "default"
 	VendorTypeNotSpecified VendorType = "vendorTypeNotSpecified"
+	VendorTypePartner      VendorType = "partner"
+	VendorTypeRedhat       VendorType = "redhat"
 )
 
 var profileMap map[VendorType][]*Profile
@@ -55,13 +58,25 @@ type Profile struct {
 type Check struct {
 	Name string              `json:"name" yaml:"name"`
 	Type apiChecks.CheckType `json:"type" yaml:"type"`
+	// Config holds the profile's default settings for the check, which can be overridden using --set.
+	Config map[string]interface{} `json:"config,omitempty" yaml:"config,omitempty"`
 }
 
 type FilteredRegistry map[apiChecks.CheckName]checks.Check
 
+// IsCertification returns true if the profiles of the vendor type certify charts.
+func IsCertification(vendorType VendorType) bool {
+	return vendorType == VendorTypePartner || vendorType == VendorTypeRedhat
+}
+
 var profileInUse *Profile
 
+// profileMutex guards the profile in use, charts being verified concurrently.
+var profileMutex sync.RWMutex
+
 func Get() *Profile {
+	profileMutex.RLock()
+	defer profileMutex.RUnlock()
 	if profileInUse == nil {
 		return getDefaultProfile("No profile set for get")
 	}
@@ -87,6 +102,9 @@ func New(values map[string]interface{}) *Profile {
 		}
 	}
 
+	profileMutex.Lock()
+	defer profileMutex.Unlock()
+
 	profileInUse = getDefaultProfile(fmt.Sprintf("profile %s not found", profileVendorType))
 
 	if vendorProfiles, ok := profileMap[profileVendorType]; ok {
@@ -146,6 +164,7 @@ func (profile *Profile) FilterChecks(registry checks.DefaultRegistry) FilteredRe
 		checkIndex := checks.CheckId{Name: apiChecks.CheckName(splitCheck[1]), Version: splitCheck[0]}
 		if newCheck, ok := registry[checkIndex]; ok {
 			newCheck.Type = check.Type
+			newCheck.Config = check.Config
 			filteredChecks[checkIndex.Name] = newCheck
 		}
 	}
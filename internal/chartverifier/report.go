@@ -54,14 +54,32 @@ func (ir *InternalReport) AddCheck(check checks.Check) *InternalCheckReport {
 }
 
 func (cr *InternalCheckReport) SetResult(outcome bool, skipped bool, reason string) {
+	cr.APICheckReport.Outcome = getOutcome(outcome, skipped)
+	cr.APICheckReport.Reason = reason
+}
+
+// SetClusterUnavailable records whether the check failed because its cluster could not be reached.
+func (cr *InternalCheckReport) SetClusterUnavailable(clusterUnavailable bool) {
+	cr.APICheckReport.ClusterUnavailable = clusterUnavailable
+}
+
+func (cr *InternalCheckReport) AddSubchartResult(subchartResult checks.SubchartResult) {
+	cr.APICheckReport.Subcharts = append(cr.APICheckReport.Subcharts, &apiReport.SubchartCheckReport{
+		Subchart: subchartResult.Subchart,
+		Check:    apiChecks.CheckName(fmt.Sprintf("%s/%s", subchartResult.Check.CheckId.Version, subchartResult.Check.CheckId.Name)),
+		Type:     subchartResult.Check.Type,
+		Outcome:  getOutcome(subchartResult.Result.Ok, subchartResult.Result.Skipped),
+		Reason:   subchartResult.Result.Reason,
+	})
+}
+
+func getOutcome(outcome bool, skipped bool) apiReport.OutcomeType {
 	if skipped {
-		cr.APICheckReport.Outcome = apiReport.SkippedOutcomeType
+		return apiReport.SkippedOutcomeType
 	} else if outcome {
-		cr.APICheckReport.Outcome = apiReport.PassOutcomeType
-	} else {
-		cr.APICheckReport.Outcome = apiReport.FailOutcomeType
+		return apiReport.PassOutcomeType
 	}
-	cr.APICheckReport.Reason = reason
+	return apiReport.FailOutcomeType
 }
 
 func (ir *InternalReport) GetApiReport() *apiReport.Report {
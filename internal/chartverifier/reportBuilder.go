@@ -44,9 +44,13 @@ type ReportBuilder interface {
 	AddCheck(check checks.Check, result checks.Result) ReportBuilder
 	SetChart(chart *helmchart.Chart) ReportBuilder
 	SetTestedOpenShiftVersion(version string) ReportBuilder
+	SetEmulatedOpenShiftVersion(version string) ReportBuilder
 	SetSupportedOpenShiftVersions(versions string) ReportBuilder
+	SetTestedCluster(distribution, platform, version string) ReportBuilder
+	SetVersionMap(version string) ReportBuilder
 	SetWebCatalogOnly(webCatalogOnly bool) ReportBuilder
 	SetPublicKeyDigest(digest string) ReportBuilder
+	SetBaseline(baseline *Baseline) ReportBuilder
 	Build() (*apiReport.Report, error)
 }
 
@@ -59,8 +63,12 @@ type reportBuilder struct {
 	Chart                *helmchart.Chart
 	Report               InternalReport
 	OCPVersion           string
+	OCPVersionEmulated   bool
 	SupportedOCPVersions string
 	PublicKey            string
+	Baseline             *Baseline
+	// BaselineApplied are the entries of the baseline which accepted a failure.
+	BaselineApplied []*BaselineEntry
 }
 
 func NewReportBuilder() ReportBuilder {
@@ -74,11 +82,33 @@ func (r *reportBuilder) SetTestedOpenShiftVersion(version string) ReportBuilder
 	return r
 }
 
+// SetEmulatedOpenShiftVersion sets the OpenShift version emulated by the cluster the chart was tested on, reported as
+// the tested version of an emulated cluster but not certified.
+func (r *reportBuilder) SetEmulatedOpenShiftVersion(version string) ReportBuilder {
+	r.OCPVersion = version
+	r.OCPVersionEmulated = true
+	return r
+}
+
 func (r *reportBuilder) SetSupportedOpenShiftVersions(versions string) ReportBuilder {
 	r.SupportedOCPVersions = versions
 	return r
 }
 
+func (r *reportBuilder) SetTestedCluster(distribution, platform, version string) ReportBuilder {
+	r.Report.GetApiReport().Metadata.ToolMetadata.TestedCluster = &apiReport.ClusterMetadata{
+		Distribution: distribution,
+		Platform:     platform,
+		Version:      version,
+	}
+	return r
+}
+
+func (r *reportBuilder) SetVersionMap(version string) ReportBuilder {
+	r.Report.GetApiReport().Metadata.ToolMetadata.VersionMap = version
+	return r
+}
+
 func (r *reportBuilder) SetToolVersion(version string) ReportBuilder {
 	r.Report.GetApiReport().Metadata.ToolMetadata.Version = version
 	return r
@@ -111,9 +141,28 @@ func (r *reportBuilder) SetPublicKeyDigest(digest string) ReportBuilder {
 	return r
 }
 
+// SetBaseline sets the baseline accepting known failures of the checks added.
+func (r *reportBuilder) SetBaseline(baseline *Baseline) ReportBuilder {
+	r.Baseline = baseline
+	return r
+}
+
 func (r *reportBuilder) AddCheck(check checks.Check, result checks.Result) ReportBuilder {
 	checkReport := r.Report.AddCheck(check)
 	checkReport.SetResult(result.Ok, result.Skipped, result.Reason)
+	checkReport.SetClusterUnavailable(result.ClusterUnavailable)
+	for _, subchartResult := range result.Subcharts {
+		checkReport.AddSubchartResult(subchartResult)
+	}
+	if apiCheckReport := checkReport.GetApiCheckReport(); apiCheckReport.Outcome == apiReport.FailOutcomeType {
+		apiCheckReport.Fingerprint = GetFingerprint(apiCheckReport)
+		if r.Baseline != nil {
+			vendorType := profiles.VendorType(r.Report.GetApiReport().Metadata.ToolMetadata.Profile.VendorType)
+			if entry := r.Baseline.Apply(apiCheckReport, time.Now(), !profiles.IsCertification(vendorType)); entry != nil {
+				r.addBaselineApplied(entry)
+			}
+		}
+	}
 	utils.LogInfo(fmt.Sprintf("Check: %s:%s result : %t", check.CheckId.Name, check.CheckId.Version, result.Ok))
 	if !result.Ok {
 		utils.LogInfo(fmt.Sprintf("Check: %s:%s reason : %s", check.CheckId.Name, check.CheckId.Version, result.Reason))
@@ -121,6 +170,16 @@ func (r *reportBuilder) AddCheck(check checks.Check, result checks.Result) Repor
 	return r
 }
 
+// addBaselineApplied records an entry of the baseline which accepted a failure, once.
+func (r *reportBuilder) addBaselineApplied(entry *BaselineEntry) {
+	for _, applied := range r.BaselineApplied {
+		if applied == entry {
+			return
+		}
+	}
+	r.BaselineApplied = append(r.BaselineApplied, entry)
+}
+
 func (r *reportBuilder) Build() (*apiReport.Report, error) {
 
 	apiReport := r.Report.GetApiReport()
@@ -132,7 +191,7 @@ func (r *reportBuilder) Build() (*apiReport.Report, error) {
 		case profiles.LastCertifiedTimestampAnnotation:
 			apiReport.Metadata.ToolMetadata.LastCertifiedTimestamp = time.Now().Format("2006-01-02T15:04:05.999999-07:00")
 		case profiles.OCPVersionAnnotation:
-			if len(r.OCPVersion) == 0 {
+			if len(r.OCPVersion) == 0 || r.OCPVersionEmulated {
 				apiReport.Metadata.ToolMetadata.CertifiedOpenShiftVersions = "N/A"
 			} else {
 				apiReport.Metadata.ToolMetadata.CertifiedOpenShiftVersions = r.OCPVersion
@@ -152,8 +211,19 @@ func (r *reportBuilder) Build() (*apiReport.Report, error) {
 		}
 	}
 
+	if r.OCPVersionEmulated {
+		if apiReport.Metadata.ToolMetadata.TestedCluster == nil {
+			r.SetTestedCluster("", "", "")
+		}
+		apiReport.Metadata.ToolMetadata.TestedCluster.Emulated = true
+	}
+
 	apiReport.Metadata.ToolMetadata.Digests.Package = GetPackageDigest(apiReport.Metadata.ToolMetadata.ChartUri)
 
+	if r.Baseline != nil {
+		apiReport.Metadata.ToolMetadata.Baseline = r.Baseline.GetMetadata(r.BaselineApplied)
+	}
+
 	if apiReport.Metadata.ToolMetadata.WebCatalogOnly {
 		r.SetChartUri(("N/A"))
 	}
@@ -12,6 +12,9 @@ import (
 	"helm.sh/helm/v3/pkg/cli"
 
 	"github.com/redhat-certification/chart-verifier/internal/chartverifier/checks"
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier/profiles"
+	apiChecks "github.com/redhat-certification/chart-verifier/pkg/chartverifier/checks"
+	apiReport "github.com/redhat-certification/chart-verifier/pkg/chartverifier/report"
 	"github.com/stretchr/testify/require"
 )
 
@@ -98,3 +101,87 @@ func TestUrlPackageDigest(t *testing.T) {
 	}
 
 }
+
+func TestAddSubchartResult(t *testing.T) {
+
+	report := newReport()
+	check := checks.Check{CheckId: checks.CheckId{Name: apiChecks.DependenciesAreValid, Version: "v1.0"}, Type: apiChecks.MandatoryCheckType}
+	checkReport := report.AddCheck(check)
+	checkReport.SetResult(true, false, checks.DependenciesValid)
+	digest, err := report.GetApiReport().GetReportDigest()
+	require.NoError(t, err)
+
+	readme := checks.Check{CheckId: checks.CheckId{Name: apiChecks.HasReadme, Version: "v1.0"}, Type: apiChecks.OptionalCheckType}
+	checkReport.AddSubchartResult(checks.SubchartResult{Subchart: "charts/sub", Check: readme, Result: checks.NewResult(false, checks.ReadmeDoesNotExist)})
+
+	require.Equal(t, []*apiReport.SubchartCheckReport{{Subchart: "charts/sub", Check: "v1.0/has-readme", Type: apiChecks.OptionalCheckType,
+		Outcome: apiReport.FailOutcomeType, Reason: checks.ReadmeDoesNotExist}}, checkReport.GetApiCheckReport().Subcharts)
+
+	subchartsDigest, err := report.GetApiReport().GetReportDigest()
+	require.NoError(t, err)
+	require.NotEqual(t, digest, subchartsDigest)
+}
+
+func TestEmulatedOpenShiftVersion(t *testing.T) {
+
+	helmChart, _, err := checks.LoadChartFromURI(&checks.CheckOptions{URI: "checks/chart-0.1.0-v3.valid.tgz", ViperConfig: viper.New(), HelmEnvSettings: cli.New()})
+	require.NoError(t, err)
+	defer profiles.New(nil)
+
+	// the partner v1.0 profile reports the certified version, the default profile the tested version.
+	profiles.New(map[string]interface{}{profiles.VendorTypeConfigName: "partner", profiles.VersionConfigName: "v1.0"})
+	report, err := NewReportBuilder().SetChart(helmChart).SetEmulatedOpenShiftVersion("4.12").Build()
+	require.NoError(t, err)
+	require.Equal(t, "N/A", report.Metadata.ToolMetadata.CertifiedOpenShiftVersions)
+	report, err = NewReportBuilder().SetChart(helmChart).SetTestedOpenShiftVersion("4.12").Build()
+	require.NoError(t, err)
+	require.Equal(t, "4.12", report.Metadata.ToolMetadata.CertifiedOpenShiftVersions)
+
+	profiles.New(nil)
+	report, err = NewReportBuilder().SetChart(helmChart).SetEmulatedOpenShiftVersion("4.12").Build()
+	require.NoError(t, err)
+	require.Equal(t, "4.12", report.Metadata.ToolMetadata.TestedOpenShiftVersion)
+	require.True(t, report.Metadata.ToolMetadata.TestedCluster.Emulated)
+	report, err = NewReportBuilder().SetChart(helmChart).SetTestedCluster("kind", "", "v1.25.0").SetEmulatedOpenShiftVersion("4.12").Build()
+	require.NoError(t, err)
+	require.Equal(t, apiReport.ClusterMetadata{Distribution: "kind", Version: "v1.25.0", Emulated: true}, *report.Metadata.ToolMetadata.TestedCluster)
+	report, err = NewReportBuilder().SetChart(helmChart).SetTestedOpenShiftVersion("4.12").Build()
+	require.NoError(t, err)
+	require.Equal(t, "4.12", report.Metadata.ToolMetadata.TestedOpenShiftVersion)
+	require.Nil(t, report.Metadata.ToolMetadata.TestedCluster)
+}
+
+func TestBaselineMetadata(t *testing.T) {
+
+	helmChart, _, err := checks.LoadChartFromURI(&checks.CheckOptions{URI: "checks/chart-0.1.0-v3.valid.tgz", ViperConfig: viper.New(), HelmEnvSettings: cli.New()})
+	require.NoError(t, err)
+	lint := checks.Check{CheckId: checks.CheckId{Name: apiChecks.HelmLint, Version: "v1.0"}, Type: apiChecks.MandatoryCheckType}
+
+	report, err := NewReportBuilder().SetChart(helmChart).SetProfile(profiles.VendorTypePartner, "v1.2").Build()
+	require.NoError(t, err)
+	require.Nil(t, report.Metadata.ToolMetadata.Baseline)
+
+	baseline, err := loadTestBaseline(t, "accepted:\n  - check: helm-lint\n")
+	require.NoError(t, err)
+
+	// the failure of a mandatory check is only accepted outside of the certification profiles.
+	report, err = NewReportBuilder().SetChart(helmChart).SetProfile(profiles.VendorTypePartner, "v1.2").SetBaseline(baseline).
+		AddCheck(lint, checks.NewResult(false, "Helm lint has failed")).Build()
+	require.NoError(t, err)
+	require.Equal(t, apiReport.FailOutcomeType, report.Results[0].Outcome)
+	require.Empty(t, report.Metadata.ToolMetadata.Baseline.Applied)
+
+	report, err = NewReportBuilder().SetChart(helmChart).SetProfile("community", "v1.2").SetBaseline(baseline).
+		AddCheck(lint, checks.NewResult(false, "Helm lint has failed")).Build()
+	require.NoError(t, err)
+	require.Equal(t, apiReport.AcceptedOutcomeType, report.Results[0].Outcome)
+	require.Equal(t, []*apiReport.BaselineEntry{{Check: "helm-lint"}}, report.Metadata.ToolMetadata.Baseline.Applied)
+
+	// the baseline is part of the report digest.
+	digest, err := report.GetReportDigest()
+	require.NoError(t, err)
+	report.Metadata.ToolMetadata.Baseline.Applied = nil
+	appliedDigest, err := report.GetReportDigest()
+	require.NoError(t, err)
+	require.NotEqual(t, digest, appliedDigest)
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path"
+	"sort"
+	"time"
+)
+
+// WriteArchive writes the files to a gzipped tarball in the output directory, next to the report and the error log,
+// and returns the path of the tarball.
+func WriteArchive(fileName string, files map[string][]byte) (string, error) {
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	outputDir := path.Join(currentDir, outputDirectory)
+	// #nosec G301
+	if err = os.MkdirAll(outputDir, 0777); err != nil {
+		return "", err
+	}
+	outputFile := path.Join(outputDir, fileName)
+
+	// #nosec G304
+	file, err := os.OpenFile(outputFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	for _, name := range names {
+		header := &tar.Header{Name: name, Mode: 0600, Size: int64(len(files[name])), ModTime: now}
+		if err = tarWriter.WriteHeader(header); err != nil {
+			return "", err
+		}
+		if _, err = tarWriter.Write(files[name]); err != nil {
+			return "", err
+		}
+	}
+	if err = tarWriter.Close(); err != nil {
+		return "", err
+	}
+	if err = gzipWriter.Close(); err != nil {
+		return "", err
+	}
+	return outputFile, nil
+}
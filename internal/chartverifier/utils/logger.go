@@ -14,6 +14,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -31,6 +32,9 @@ var CmdStdout io.Writer = os.Stdout
 var CmdStderr io.Writer = os.Stderr
 
 var verifierlog VerifierLog
+
+// logMutex guards the entries of the verifier log, releases being installed and tested concurrently.
+var logMutex sync.Mutex
 var cmd *cobra.Command
 var stdoutFileName string
 var stderrFileName string
@@ -55,12 +59,12 @@ func LogWarning(message string) {
 		cmd.PrintErrln(message)
 	}
 	warning_log_entry := LogEntry{Entry: fmt.Sprintf("[WARNING] %s : %s", getTimeStamp(), message)}
-	verifierlog.Entries = append(verifierlog.Entries, &warning_log_entry)
+	addLogEntry(&warning_log_entry)
 }
 
 func LogInfo(message string) {
 	info_log_entry := LogEntry{Entry: fmt.Sprintf("[INFO] %s : %s", getTimeStamp(), message)}
-	verifierlog.Entries = append(verifierlog.Entries, &info_log_entry)
+	addLogEntry(&info_log_entry)
 }
 
 func LogError(message string) {
@@ -68,24 +72,34 @@ func LogError(message string) {
 		cmd.PrintErrln(message)
 	}
 	error_log_entry := LogEntry{Entry: fmt.Sprintf("[ERROR] %s : %s", getTimeStamp(), message)}
-	verifierlog.Entries = append(verifierlog.Entries, &error_log_entry)
+	addLogEntry(&error_log_entry)
+}
+
+func addLogEntry(entry *LogEntry) {
+	logMutex.Lock()
+	defer logMutex.Unlock()
+	verifierlog.Entries = append(verifierlog.Entries, entry)
 }
 
 func WriteLogs(log_format string) {
 
 	pruneLogFiles()
 
-	if len(verifierlog.Entries) > 0 && len(stderrFileName) > 0 {
+	logMutex.Lock()
+	log := VerifierLog{Name: verifierlog.Name, Time: verifierlog.Time, Entries: append([]*LogEntry{}, verifierlog.Entries...)}
+	logMutex.Unlock()
+
+	if len(log.Entries) > 0 && len(stderrFileName) > 0 {
 		logOut := ""
 		if log_format == "json" {
-			b, err := json.Marshal(&verifierlog)
+			b, err := json.Marshal(&log)
 			if err != nil {
 				LogError(err.Error())
 				return
 			}
 			logOut = string(b)
 		} else {
-			b, err := yaml.Marshal(&verifierlog)
+			b, err := yaml.Marshal(&log)
 			if err != nil {
 				LogError(err.Error())
 				return
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -245,3 +246,18 @@ func howManyLogFiles() int {
 	}
 	return numLogFiles
 }
+
+func TestConcurrentLogging(t *testing.T) {
+
+	InitLog(&cobra.Command{}, "", true)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			LogInfo(fmt.Sprintf("entry %d", i))
+		}(i)
+	}
+	wg.Wait()
+	require.Len(t, verifierlog.Entries, 50)
+}
@@ -54,6 +54,10 @@ func (holder *AnnotationHolder) SetCertifiedOpenShiftVersion(version string) {
 	holder.Holder.SetTestedOpenShiftVersion(version)
 }
 
+func (holder *AnnotationHolder) SetEmulatedOpenShiftVersion(version string) {
+	holder.Holder.SetEmulatedOpenShiftVersion(version)
+}
+
 func (holder *AnnotationHolder) GetCertifiedOpenShiftVersionFlag() string {
 	return holder.CertifiedOpenShiftVersionFlag
 }
@@ -62,6 +66,10 @@ func (holder *AnnotationHolder) SetSupportedOpenShiftVersions(versions string) {
 	holder.Holder.SetSupportedOpenShiftVersions(versions)
 }
 
+func (holder *AnnotationHolder) SetTestedCluster(distribution, platform, version string) {
+	holder.Holder.SetTestedCluster(distribution, platform, version)
+}
+
 type verifier struct {
 	config             *viper.Viper
 	registry           checks.Registry
@@ -73,7 +81,11 @@ type verifier struct {
 	webCatalogOnly     bool
 	timeout            time.Duration
 	helmInstallTimeout time.Duration
+	keepOnFailure      bool
+	ephemeralCluster   bool
 	publicKeys         []string
+	versionMap         string
+	baseline           string
 	values             map[string]interface{}
 }
 
@@ -85,6 +97,16 @@ func (c *verifier) subConfig(name string) *viper.Viper {
 	}
 }
 
+// checkConfig returns the configuration of a check, the profile's settings for the check overridden by any settings
+// from the command line.
+func (c *verifier) checkConfig(check checks.Check) *viper.Viper {
+	config := c.subConfig(string(check.CheckId.Name))
+	for key, value := range check.Config {
+		config.SetDefault(key, value)
+	}
+	return config
+}
+
 func (c *verifier) Verify(uri string) (*apiReport.Report, error) {
 
 	if c.webCatalogOnly {
@@ -93,6 +115,19 @@ func (c *verifier) Verify(uri string) (*apiReport.Report, error) {
 		}
 	}
 
+	// the map embedded in the verifier is loaded when no version map is set, replacing the map of a previous run.
+	if err := tool.LoadVersionMap(c.versionMap); err != nil {
+		return nil, err
+	}
+
+	var baseline *Baseline
+	if len(c.baseline) > 0 {
+		var err error
+		if baseline, err = LoadBaseline(c.baseline); err != nil {
+			return nil, err
+		}
+	}
+
 	chrt, _, err := checks.LoadChartFromURI(&checks.CheckOptions{HelmEnvSettings: c.settings, URI: uri})
 	if err != nil {
 		return nil, err
@@ -103,7 +138,9 @@ func (c *verifier) Verify(uri string) (*apiReport.Report, error) {
 		SetChartUri(uri).
 		SetChart(chrt).
 		SetProfile(c.profile.Vendor, c.profile.Version).
-		SetWebCatalogOnly(c.webCatalogOnly)
+		SetWebCatalogOnly(c.webCatalogOnly).
+		SetVersionMap(tool.GetVersionMapVersion()).
+		SetBaseline(baseline)
 
 	for _, check := range c.requiredChecks {
 
@@ -117,11 +154,14 @@ func (c *verifier) Verify(uri string) (*apiReport.Report, error) {
 			HelmEnvSettings:    c.settings,
 			URI:                uri,
 			Values:             c.values,
-			ViperConfig:        c.subConfig(string(check.CheckId.Name)),
+			ViperConfig:        c.checkConfig(check),
 			AnnotationHolder:   &holder,
 			Timeout:            c.timeout,
 			HelmInstallTimeout: c.helmInstallTimeout,
+			KeepOnFailure:      c.keepOnFailure,
+			EphemeralCluster:   c.ephemeralCluster,
 			PublicKeys:         c.publicKeys,
+			ProfileChecks:      c.requiredChecks,
 		})
 
 		if checkErr != nil {
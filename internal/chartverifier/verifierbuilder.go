@@ -50,6 +50,15 @@ func init() {
 	defaultRegistry.Add(apiChecks.ChartTesting, "v1.0", checks.ChartTesting)
 	defaultRegistry.Add(apiChecks.RequiredAnnotationsPresent, "v1.0", checks.RequiredAnnotationsPresent)
 	defaultRegistry.Add(apiChecks.SignatureIsValid, "v1.0", checks.SignatureIsValid)
+	defaultRegistry.Add(apiChecks.RunsWithRestrictedSCC, "v1.0", checks.CanBeInstalledWithoutClusterAdminPrivileges)
+	defaultRegistry.Add(apiChecks.HasLeastPrivilegeRBAC, "v1.0", checks.HasLeastPrivilegeRBAC)
+	defaultRegistry.Add(apiChecks.KeywordsAreOpenshiftCategories, "v1.0", checks.KeywordsAreOpenshiftCategories)
+	defaultRegistry.Add(apiChecks.IsCommercialChart, "v1.0", checks.IsCommercialChart)
+	defaultRegistry.Add(apiChecks.IsCommunityChart, "v1.0", checks.IsCommunityChart)
+	defaultRegistry.Add(apiChecks.HasResourceRequirements, "v1.0", checks.HasResourceRequirements)
+	defaultRegistry.Add(apiChecks.DependenciesAreValid, "v1.0", checks.DependenciesAreValid)
+	defaultRegistry.Add(apiChecks.HasCompleteMetadata, "v1.0", checks.HasCompleteMetadata)
+	defaultRegistry.Add(apiChecks.HasCompleteReadme, "v1.0", checks.HasCompleteReadme)
 }
 
 func DefaultRegistry() checks.Registry {
@@ -69,6 +78,10 @@ type verifierBuilder struct {
 	timeout                     time.Duration
 	publicKeys                  []string
 	helmInstallTimeout          time.Duration
+	keepOnFailure               bool
+	ephemeralCluster            bool
+	versionMap                  string
+	baseline                    string
 	values                      map[string]interface{}
 	settings                    *cli.EnvSettings
 }
@@ -137,6 +150,26 @@ func (b *verifierBuilder) SetHelmInstallTimeout(timeout time.Duration) VerifierB
 	return b
 }
 
+func (b *verifierBuilder) SetKeepOnFailure(keepOnFailure bool) VerifierBuilder {
+	b.keepOnFailure = keepOnFailure
+	return b
+}
+
+func (b *verifierBuilder) SetEphemeralCluster(ephemeralCluster bool) VerifierBuilder {
+	b.ephemeralCluster = ephemeralCluster
+	return b
+}
+
+func (b *verifierBuilder) SetVersionMap(versionMap string) VerifierBuilder {
+	b.versionMap = versionMap
+	return b
+}
+
+func (b *verifierBuilder) SetBaseline(baseline string) VerifierBuilder {
+	b.baseline = baseline
+	return b
+}
+
 func (b *verifierBuilder) GetConfig() *viper.Viper {
 	return b.config
 }
@@ -177,7 +210,11 @@ func (b *verifierBuilder) Build() (Verifier, error) {
 		webCatalogOnly:     b.webCatalogOnly,
 		timeout:            b.timeout,
 		helmInstallTimeout: b.helmInstallTimeout,
+		keepOnFailure:      b.keepOnFailure,
+		ephemeralCluster:   b.ephemeralCluster,
 		publicKeys:         b.publicKeys,
+		versionMap:         b.versionMap,
+		baseline:           b.baseline,
 		values:             b.values,
 	}, nil
 }
@@ -0,0 +1,163 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier/utils"
+)
+
+// ReleaseObject identifies an object created by a release.
+type ReleaseObject struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+}
+
+func (o ReleaseObject) String() string {
+	if len(o.Namespace) == 0 {
+		return fmt.Sprintf("%s/%s", o.Kind, o.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", o.Kind, o.Namespace, o.Name)
+}
+
+// manifestHead is the part of a manifest identifying its object.
+type manifestHead struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+}
+
+// GetManifestObjects returns the objects of a manifest, made of one or more yaml documents. Objects without a namespace
+// are given the namespace, it is ignored when the objects are deleted if they are cluster-scoped.
+func GetManifestObjects(manifest, namespace string) ([]ReleaseObject, error) {
+	manifests := releaseutil.SplitManifests(manifest)
+	keys := make([]string, 0, len(manifests))
+	for key := range manifests {
+		keys = append(keys, key)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	objects := make([]ReleaseObject, 0, len(manifests))
+	for _, key := range keys {
+		var head manifestHead
+		if err := yaml.Unmarshal([]byte(manifests[key]), &head); err != nil {
+			return nil, err
+		}
+		if len(head.Kind) == 0 || len(head.Metadata.Name) == 0 {
+			continue
+		}
+		object := ReleaseObject{APIVersion: head.APIVersion, Kind: head.Kind, Namespace: head.Metadata.Namespace, Name: head.Metadata.Name}
+		if len(object.Namespace) == 0 {
+			object.Namespace = namespace
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+// sortObjectsForDeletion sorts the objects in the order helm uninstalls them, objects of unknown kinds, custom
+// resources, first so that their controllers and definitions are still there to run their finalizers.
+func sortObjectsForDeletion(objects []ReleaseObject) {
+	ordering := make(map[string]int, len(releaseutil.UninstallOrder))
+	for index, kind := range releaseutil.UninstallOrder {
+		ordering[kind] = index + 1
+	}
+	sort.SliceStable(objects, func(i, j int) bool {
+		return ordering[objects[i].Kind] < ordering[objects[j].Kind]
+	})
+}
+
+// DeleteObjects deletes the objects in dependency order, then waits for them to be gone, finalizers included, until
+// the context is done. It returns a description of each object left over.
+func (k Kubectl) DeleteObjects(ctx context.Context, objects []ReleaseObject) []string {
+	objects = append([]ReleaseObject{}, objects...)
+	sortObjectsForDeletion(objects)
+
+	leftovers := make([]string, 0)
+	resources := make(map[ReleaseObject]dynamic.ResourceInterface)
+	propagation := metav1.DeletePropagationBackground
+	for _, object := range objects {
+		resource, err := k.getResource(object)
+		if err != nil {
+			leftovers = append(leftovers, fmt.Sprintf("%s: %v", object, err))
+			continue
+		}
+		err = resource.Delete(ctx, object.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+		if err != nil && !errors.IsNotFound(err) {
+			leftovers = append(leftovers, fmt.Sprintf("%s: %v", object, err))
+			continue
+		}
+		resources[object] = resource
+	}
+
+	for _, object := range objects {
+		resource, ok := resources[object]
+		if !ok {
+			continue
+		}
+		if leftover := waitForDelete(ctx, resource, object); len(leftover) > 0 {
+			leftovers = append(leftovers, leftover)
+		}
+	}
+	for _, leftover := range leftovers {
+		utils.LogWarning(fmt.Sprintf("Left over after cleanup: %s", leftover))
+	}
+	return leftovers
+}
+
+// waitForDelete polls the object until it is gone, returning a description of the object if it is still there when
+// the context is done.
+func waitForDelete(ctx context.Context, resource dynamic.ResourceInterface, object ReleaseObject) string {
+	backoff := waitInitialBackoff
+	for {
+		current, err := resource.Get(ctx, object.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return ""
+		}
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Sprintf("%s: %v", object, err)
+			}
+			if finalizers := current.GetFinalizers(); len(finalizers) > 0 {
+				return fmt.Sprintf("%s: still present, finalizers %v", object, finalizers)
+			}
+			return fmt.Sprintf("%s: still present", object)
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > waitMaxBackoff {
+			backoff = waitMaxBackoff
+		}
+	}
+}
+
+// getResource returns the client of the resource of the object, namespaced if its kind is.
+func (k Kubectl) getResource(object ReleaseObject) (dynamic.ResourceInterface, error) {
+	if k.dynamicClient == nil || k.mapper == nil {
+		return nil, fmt.Errorf("no client to delete %s", object.Kind)
+	}
+	gvk := schema.FromAPIVersionAndKind(object.APIVersion, object.Kind)
+	mapping, err := k.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return k.dynamicClient.Resource(mapping.Resource).Namespace(object.Namespace), nil
+	}
+	return k.dynamicClient.Resource(mapping.Resource), nil
+}
@@ -0,0 +1,135 @@
+package tool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const releaseManifest = `---
+# Source: chart/templates/clusterrole.yaml
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: chart-reader
+---
+# Source: chart/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: chart-config
+---
+# Source: chart/templates/widget.yaml
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: chart-widget
+  namespace: other
+---
+# Source: chart/templates/empty.yaml
+`
+
+func newUnstructured(apiVersion, kind, namespace, name string, finalizers ...string) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{}
+	object.SetAPIVersion(apiVersion)
+	object.SetKind(kind)
+	object.SetNamespace(namespace)
+	object.SetName(name)
+	object.SetFinalizers(finalizers)
+	return object
+}
+
+func newCleanupKubectl(objects ...runtime.Object) (Kubectl, *dynamicfake.FakeDynamicClient) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}, meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}, meta.RESTScopeNamespace)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objects...)
+	return Kubectl{dynamicClient: dynamicClient, mapper: mapper}, dynamicClient
+}
+
+func TestGetManifestObjects(t *testing.T) {
+
+	objects, err := GetManifestObjects(releaseManifest, "test")
+	require.NoError(t, err)
+	require.Equal(t, []ReleaseObject{
+		{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Namespace: "test", Name: "chart-reader"},
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "test", Name: "chart-config"},
+		{APIVersion: "example.com/v1", Kind: "Widget", Namespace: "other", Name: "chart-widget"},
+	}, objects)
+
+	_, err = GetManifestObjects("kind: [", "test")
+	require.Error(t, err)
+}
+
+func TestSortObjectsForDeletion(t *testing.T) {
+
+	objects := []ReleaseObject{
+		{Kind: "Namespace", Name: "test"},
+		{Kind: "ClusterRole", Name: "chart-reader"},
+		{Kind: "Deployment", Name: "chart"},
+		{Kind: "Widget", Name: "chart-widget"},
+		{Kind: "ConfigMap", Name: "chart-config"},
+	}
+	sortObjectsForDeletion(objects)
+	kinds := make([]string, 0, len(objects))
+	for _, object := range objects {
+		kinds = append(kinds, object.Kind)
+	}
+	require.Equal(t, []string{"Widget", "Deployment", "ClusterRole", "ConfigMap", "Namespace"}, kinds)
+}
+
+func TestDeleteObjects(t *testing.T) {
+
+	kubectl, dynamicClient := newCleanupKubectl(
+		newUnstructured("v1", "ConfigMap", "test", "chart-config"),
+		newUnstructured("rbac.authorization.k8s.io/v1", "ClusterRole", "", "chart-reader"),
+		newUnstructured("v1", "Namespace", "", "test"),
+	)
+	objects, err := GetManifestObjects(releaseManifest, "test")
+	require.NoError(t, err)
+	objects = append(objects, ReleaseObject{APIVersion: "v1", Kind: "Namespace", Name: "test"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// the widget was already deleted by helm uninstall.
+	require.Empty(t, kubectl.DeleteObjects(ctx, objects))
+
+	deleted := make([]string, 0)
+	for _, action := range dynamicClient.Actions() {
+		if deleteAction, ok := action.(k8stesting.DeleteAction); ok {
+			deleted = append(deleted, deleteAction.GetResource().Resource+"/"+deleteAction.GetName())
+		}
+	}
+	require.Equal(t, []string{"widgets/chart-widget", "clusterroles/chart-reader", "configmaps/chart-config", "namespaces/test"}, deleted)
+}
+
+func TestDeleteObjectsLeftovers(t *testing.T) {
+
+	kubectl, dynamicClient := newCleanupKubectl(newUnstructured("example.com/v1", "Widget", "other", "chart-widget", "example.com/cleanup"))
+	// the finalizer of the widget is never removed.
+	dynamicClient.PrependReactor("delete", "widgets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, nil
+	})
+	objects := []ReleaseObject{
+		{APIVersion: "example.com/v1", Kind: "Widget", Namespace: "other", Name: "chart-widget"},
+		{APIVersion: "example.com/v1", Kind: "Gadget", Namespace: "other", Name: "chart-gadget"},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	leftovers := kubectl.DeleteObjects(ctx, objects)
+	require.Len(t, leftovers, 2)
+	require.Contains(t, leftovers[0], "Gadget/other/chart-gadget: no matches for kind")
+	require.Equal(t, "Widget/other/chart-widget: still present, finalizers [example.com/cleanup]", leftovers[1])
+}
@@ -0,0 +1,112 @@
+package tool
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/helm/chart-testing/v3/pkg/util"
+
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier/utils"
+)
+
+const (
+	kindExecutable        = "kind"
+	kindClusterNamePrefix = "chart-verifier-"
+	kindWaitTimeout       = 5 * time.Minute
+)
+
+// EphemeralCluster is a throwaway local kind cluster, running the Kubernetes version of an OpenShift version, which
+// charts are tested on rather than on the cluster of the kubeconfig.
+type EphemeralCluster struct {
+	Name        string
+	KubeVersion string
+	NodeImage   string
+	// KubeConfig is the path of the kubeconfig of the cluster once started.
+	KubeConfig string
+	executor   ProcessExecutorer
+	tempDir    string
+}
+
+// GetEphemeralKubeVersion returns the Kubernetes version an ephemeral cluster runs to emulate the OpenShift version,
+// or the latest Kubernetes version a kind node image is available for if the OpenShift version is not set.
+func GetEphemeralKubeVersion(openShiftVersion string) (string, error) {
+	if len(openShiftVersion) == 0 {
+		var latest *semver.Version
+		for kubeVersion := range kindNodeImageMap {
+			version, err := semver.NewVersion(kubeVersion)
+			if err == nil && (latest == nil || version.GreaterThan(latest)) {
+				latest = version
+			}
+		}
+		if latest == nil {
+			return "", fmt.Errorf("no Kubernetes version has a kind node image")
+		}
+		return fmt.Sprintf("%d.%d", latest.Major(), latest.Minor()), nil
+	}
+
+	ocpVersion, err := semver.NewVersion(openShiftVersion)
+	if err != nil {
+		return "", fmt.Errorf("OpenShift version %s is not valid: %v", openShiftVersion, err)
+	}
+	for kubeVersion, mappedVersion := range kubeOpenShiftVersionMap {
+		if mappedVersion != fmt.Sprintf("%d.%d", ocpVersion.Major(), ocpVersion.Minor()) {
+			continue
+		}
+		if _, ok := kindNodeImageMap[kubeVersion]; !ok {
+			return "", fmt.Errorf("no kind node image for Kubernetes %s of OpenShift %s", kubeVersion, openShiftVersion)
+		}
+		return kubeVersion, nil
+	}
+	return "", fmt.Errorf("no Kubernetes version found for OpenShift %s", openShiftVersion)
+}
+
+func NewEphemeralCluster(kubeVersion string, executor ProcessExecutorer) (*EphemeralCluster, error) {
+	nodeImage, ok := kindNodeImageMap[kubeVersion]
+	if !ok {
+		return nil, fmt.Errorf("no kind node image for Kubernetes %s", kubeVersion)
+	}
+	return &EphemeralCluster{
+		Name:        kindClusterNamePrefix + util.RandomString(6),
+		KubeVersion: kubeVersion,
+		NodeImage:   nodeImage,
+		executor:    executor,
+	}, nil
+}
+
+// Start creates the cluster, with a kubeconfig of its own, and waits for its control plane to be ready.
+func (c *EphemeralCluster) Start() error {
+	utils.LogInfo(fmt.Sprintf("Create ephemeral cluster %s, Kubernetes %s, image %s", c.Name, c.KubeVersion, c.NodeImage))
+	tempDir, err := ioutil.TempDir("", "chart-verifier-cluster-*")
+	if err != nil {
+		return err
+	}
+	c.tempDir = tempDir
+	kubeConfig := path.Join(tempDir, "kubeconfig")
+
+	_, err = c.executor.RunProcessAndCaptureOutput(kindExecutable, "create", "cluster", "--name", c.Name, "--image", c.NodeImage,
+		"--kubeconfig", kubeConfig, "--wait", kindWaitTimeout.String())
+	if err != nil {
+		utils.LogError(fmt.Sprintf("Error creating ephemeral cluster %s: %v", c.Name, err))
+		os.RemoveAll(tempDir)
+		return fmt.Errorf("Error creating ephemeral cluster: %v", err)
+	}
+	c.KubeConfig = kubeConfig
+	utils.LogInfo(fmt.Sprintf("Ephemeral cluster %s created", c.Name))
+	return nil
+}
+
+// Stop deletes the cluster and its kubeconfig.
+func (c *EphemeralCluster) Stop() error {
+	utils.LogInfo(fmt.Sprintf("Delete ephemeral cluster %s", c.Name))
+	defer os.RemoveAll(c.tempDir)
+	if _, err := c.executor.RunProcessAndCaptureOutput(kindExecutable, "delete", "cluster", "--name", c.Name); err != nil {
+		utils.LogError(fmt.Sprintf("Error deleting ephemeral cluster %s: %v", c.Name, err))
+		return err
+	}
+	utils.LogInfo(fmt.Sprintf("Ephemeral cluster %s deleted", c.Name))
+	return nil
+}
@@ -0,0 +1,78 @@
+package tool
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProcessExecutor records the commands run instead of running them.
+type fakeProcessExecutor struct {
+	commands []string
+	err      error
+}
+
+func (p *fakeProcessExecutor) RunProcessAndCaptureOutput(executable string, execArgs ...interface{}) (string, error) {
+	p.commands = append(p.commands, strings.Join(append([]string{executable}, toStringArray(execArgs)...), " "))
+	return "", p.err
+}
+
+func TestGetEphemeralKubeVersion(t *testing.T) {
+
+	kubeVersion, err := GetEphemeralKubeVersion("")
+	require.NoError(t, err)
+	require.Equal(t, "1.25", kubeVersion)
+
+	kubeVersion, err = GetEphemeralKubeVersion("4.10")
+	require.NoError(t, err)
+	require.Equal(t, "1.23", kubeVersion)
+
+	kubeVersion, err = GetEphemeralKubeVersion("4.11.5")
+	require.NoError(t, err)
+	require.Equal(t, "1.24", kubeVersion)
+
+	_, err = GetEphemeralKubeVersion("4.2")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no kind node image for Kubernetes 1.14")
+
+	_, err = GetEphemeralKubeVersion("5.0")
+	require.Error(t, err)
+
+	_, err = GetEphemeralKubeVersion("latest")
+	require.Error(t, err)
+}
+
+func TestEphemeralCluster(t *testing.T) {
+
+	executor := &fakeProcessExecutor{}
+	cluster, err := NewEphemeralCluster("1.24", executor)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(cluster.Name, kindClusterNamePrefix))
+	require.Equal(t, "kindest/node:v1.24.7", cluster.NodeImage)
+
+	require.NoError(t, cluster.Start())
+	require.NotEmpty(t, cluster.KubeConfig)
+	require.Equal(t, fmt.Sprintf("kind create cluster --name %s --image kindest/node:v1.24.7 --kubeconfig %s --wait 5m0s", cluster.Name, cluster.KubeConfig), executor.commands[0])
+	_, err = os.Stat(cluster.tempDir)
+	require.NoError(t, err)
+
+	require.NoError(t, cluster.Stop())
+	require.Equal(t, fmt.Sprintf("kind delete cluster --name %s", cluster.Name), executor.commands[1])
+	_, err = os.Stat(cluster.tempDir)
+	require.True(t, os.IsNotExist(err))
+
+	_, err = NewEphemeralCluster("1.14", executor)
+	require.Error(t, err)
+
+	failing := &fakeProcessExecutor{err: errors.New("kind not found")}
+	cluster, err = NewEphemeralCluster("1.25", failing)
+	require.NoError(t, err)
+	err = cluster.Start()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "kind not found")
+	require.Empty(t, cluster.KubeConfig)
+}
@@ -0,0 +1,99 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier/utils"
+)
+
+const (
+	OpenShiftDistribution  = "OpenShift"
+	KubernetesDistribution = "Kubernetes"
+
+	clusterVersionName = "version"
+)
+
+var clusterVersionResource = schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusterversions"}
+
+// ClusterInfo describes the cluster charts are tested on.
+type ClusterInfo struct {
+	// Distribution is OpenShift, or Kubernetes for clusters which are not OpenShift.
+	Distribution string
+	// Platform is the operating system and architecture of the API server, for example linux/amd64.
+	Platform string
+	// Version is the exact version of OpenShift, or of Kubernetes for clusters which are not OpenShift.
+	Version string
+	// KubeVersion is the major.minor Kubernetes version of the API server.
+	KubeVersion string
+}
+
+// GetClusterInfo introspects the cluster: the version of the API server, and the ClusterVersion of OpenShift, the
+// cluster being plain Kubernetes if there is no ClusterVersion.
+func (k Kubectl) GetClusterInfo(ctx context.Context) (*ClusterInfo, error) {
+	serverVersion, err := k.GetServerVersion()
+	if err != nil {
+		return nil, err
+	}
+	info := &ClusterInfo{
+		Distribution: KubernetesDistribution,
+		Platform:     serverVersion.Platform,
+		Version:      serverVersion.GitVersion,
+		// managed Kubernetes services report minor versions such as "25+".
+		KubeVersion: fmt.Sprintf("%s.%s", serverVersion.Major, strings.TrimSuffix(serverVersion.Minor, "+")),
+	}
+
+	openShiftVersion, err := k.getOpenShiftVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(openShiftVersion) > 0 {
+		info.Distribution = OpenShiftDistribution
+		info.Version = openShiftVersion
+	}
+	return info, nil
+}
+
+// getOpenShiftVersion returns the version OpenShift runs, the last completed update of its ClusterVersion or the
+// desired version while it is being installed, or an empty version if the cluster is not OpenShift or its ClusterVersion
+// cannot be read.
+func (k Kubectl) getOpenShiftVersion(ctx context.Context) (string, error) {
+	if k.dynamicClient == nil {
+		return "", fmt.Errorf("no client to get the ClusterVersion")
+	}
+	clusterVersion, err := k.dynamicClient.Resource(clusterVersionResource).Get(ctx, clusterVersionName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return "", nil
+	} else if errors.IsForbidden(err) || meta.IsNoMatchError(err) {
+		// the OpenShift version is then found from the Kubernetes version in the version map.
+		utils.LogWarning(fmt.Sprintf("Cannot get the ClusterVersion, using the Kubernetes version of the cluster: %v", err))
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("error getting the ClusterVersion: %v", err)
+	}
+
+	history, _, _ := unstructured.NestedSlice(clusterVersion.Object, "status", "history")
+	for _, entry := range history {
+		update, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// the history is ordered by recency, newest first.
+		if state, _, _ := unstructured.NestedString(update, "state"); state == "Completed" {
+			if version, _, _ := unstructured.NestedString(update, "version"); len(version) > 0 {
+				return version, nil
+			}
+		}
+	}
+	if version, _, _ := unstructured.NestedString(clusterVersion.Object, "status", "desired", "version"); len(version) > 0 {
+		return version, nil
+	}
+	return "", fmt.Errorf("the ClusterVersion has no version")
+}
@@ -0,0 +1,87 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newClusterInfoKubectl(serverVersion *version.Info, objects ...runtime.Object) Kubectl {
+	clientset := fake.NewSimpleClientset()
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = serverVersion
+	return Kubectl{clientset: clientset, dynamicClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objects...)}
+}
+
+func newClusterVersion(desired string, history ...map[string]interface{}) *unstructured.Unstructured {
+	clusterVersion := newUnstructured("config.openshift.io/v1", "ClusterVersion", "", clusterVersionName)
+	entries := make([]interface{}, 0, len(history))
+	for _, entry := range history {
+		entries = append(entries, entry)
+	}
+	clusterVersion.Object["status"] = map[string]interface{}{
+		"desired": map[string]interface{}{"version": desired},
+		"history": entries,
+	}
+	return clusterVersion
+}
+
+func TestGetClusterInfo(t *testing.T) {
+
+	serverVersion := &version.Info{Major: "1", Minor: "24+", GitVersion: "v1.24.6+5658434", Platform: "linux/amd64"}
+
+	t.Run("Kubernetes cluster", func(t *testing.T) {
+		info, err := newClusterInfoKubectl(serverVersion).GetClusterInfo(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, &ClusterInfo{Distribution: KubernetesDistribution, Platform: "linux/amd64", Version: "v1.24.6+5658434", KubeVersion: "1.24"}, info)
+	})
+
+	t.Run("OpenShift cluster being updated", func(t *testing.T) {
+		clusterVersion := newClusterVersion("4.11.13",
+			map[string]interface{}{"state": "Partial", "version": "4.11.13"},
+			map[string]interface{}{"state": "Completed", "version": "4.11.12"},
+			map[string]interface{}{"state": "Completed", "version": "4.11.9"})
+		info, err := newClusterInfoKubectl(serverVersion, clusterVersion).GetClusterInfo(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, &ClusterInfo{Distribution: OpenShiftDistribution, Platform: "linux/amd64", Version: "4.11.12", KubeVersion: "1.24"}, info)
+	})
+
+	t.Run("OpenShift cluster being installed", func(t *testing.T) {
+		clusterVersion := newClusterVersion("4.12.0", map[string]interface{}{"state": "Partial", "version": "4.12.0"})
+		info, err := newClusterInfoKubectl(serverVersion, clusterVersion).GetClusterInfo(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, OpenShiftDistribution, info.Distribution)
+		require.Equal(t, "4.12.0", info.Version)
+	})
+
+	for description, getErr := range map[string]error{
+		"ClusterVersion which cannot be read": apierrors.NewForbidden(clusterVersionResource.GroupResource(), clusterVersionName, errors.New("access denied")),
+		"ClusterVersion API not served":       &meta.NoResourceMatchError{PartialResource: clusterVersionResource},
+	} {
+		t.Run(description, func(t *testing.T) {
+			kubectl := newClusterInfoKubectl(serverVersion)
+			kubectl.dynamicClient.(*dynamicfake.FakeDynamicClient).PrependReactor("get", "clusterversions", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, getErr
+			})
+			info, err := kubectl.GetClusterInfo(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, &ClusterInfo{Distribution: KubernetesDistribution, Platform: "linux/amd64", Version: "v1.24.6+5658434", KubeVersion: "1.24"}, info)
+		})
+	}
+
+	t.Run("ClusterVersion without a version", func(t *testing.T) {
+		_, err := newClusterInfoKubectl(serverVersion, newClusterVersion("")).GetClusterInfo(context.Background())
+		require.EqualError(t, err, "the ClusterVersion has no version")
+	})
+}
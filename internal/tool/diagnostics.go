@@ -0,0 +1,93 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const helmHookAnnotation = "helm.sh/hook"
+
+// helmTestHooks are the hooks of helm test pods, test-success being the name used before helm 3.
+var helmTestHooks = []string{"test", "test-success"}
+
+// CollectDiagnostics gathers the state of a namespace to debug a failed release: the pods, workloads and events of the
+// namespace as json, and the logs of each container. The logs of helm test pods are kept apart from those of the
+// release's pods. Collection is best effort, errors are recorded in errors.txt rather than returned.
+func (k Kubectl) CollectDiagnostics(ctx context.Context, namespace string) map[string][]byte {
+
+	files := make(map[string][]byte)
+	var collectErrors []string
+	addJson := func(name string, list interface{}, err error) {
+		if err != nil {
+			collectErrors = append(collectErrors, fmt.Sprintf("%s: %v", name, err))
+			return
+		}
+		content, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			collectErrors = append(collectErrors, fmt.Sprintf("%s: %v", name, err))
+			return
+		}
+		files[name] = content
+	}
+
+	listOptions := metav1.ListOptions{}
+	pods, err := k.clientset.CoreV1().Pods(namespace).List(ctx, listOptions)
+	addJson("pods.json", pods, err)
+	events, err := k.clientset.CoreV1().Events(namespace).List(ctx, listOptions)
+	if err == nil {
+		sort.SliceStable(events.Items, func(i, j int) bool {
+			return events.Items[i].LastTimestamp.Before(&events.Items[j].LastTimestamp)
+		})
+	}
+	addJson("events.json", events, err)
+	deployments, err := k.clientset.AppsV1().Deployments(namespace).List(ctx, listOptions)
+	addJson("deployments.json", deployments, err)
+	statefulSets, err := k.clientset.AppsV1().StatefulSets(namespace).List(ctx, listOptions)
+	addJson("statefulsets.json", statefulSets, err)
+	daemonSets, err := k.clientset.AppsV1().DaemonSets(namespace).List(ctx, listOptions)
+	addJson("daemonsets.json", daemonSets, err)
+	jobs, err := k.clientset.BatchV1().Jobs(namespace).List(ctx, listOptions)
+	addJson("jobs.json", jobs, err)
+
+	if pods != nil {
+		for _, pod := range pods.Items {
+			logDir := "logs"
+			if isHelmTestPod(pod) {
+				logDir = "tests"
+			}
+			containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+			for _, container := range containers {
+				name := path.Join(logDir, pod.Name, container.Name+".log")
+				logs, err := k.clientset.CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: container.Name}).DoRaw(ctx)
+				if err != nil {
+					collectErrors = append(collectErrors, fmt.Sprintf("%s: %v", name, err))
+					continue
+				}
+				files[name] = logs
+			}
+		}
+	}
+
+	if len(collectErrors) > 0 {
+		files["errors.txt"] = []byte(strings.Join(collectErrors, "\n") + "\n")
+	}
+	return files
+}
+
+func isHelmTestPod(pod corev1.Pod) bool {
+	for _, hook := range strings.Split(pod.Annotations[helmHookAnnotation], ",") {
+		for _, testHook := range helmTestHooks {
+			if strings.TrimSpace(hook) == testHook {
+				return true
+			}
+		}
+	}
+	return false
+}
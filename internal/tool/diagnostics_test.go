@@ -0,0 +1,61 @@
+package tool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCollectDiagnostics(t *testing.T) {
+
+	namespace := "test-namespace"
+	releasePod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "release-pod", Namespace: namespace},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init"}},
+			Containers:     []corev1.Container{{Name: "app"}},
+		},
+	}
+	testPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "release-test-connection",
+			Namespace:   namespace,
+			Annotations: map[string]string{helmHookAnnotation: "test"},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "wget"}}},
+	}
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "release-pod.event", Namespace: namespace},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "release-pod"},
+		Reason:         "BackOff",
+	}
+
+	kubectl := Kubectl{clientset: fake.NewSimpleClientset(releasePod, testPod, event)}
+	files := kubectl.CollectDiagnostics(context.Background(), namespace)
+
+	for _, name := range []string{"pods.json", "events.json", "deployments.json", "statefulsets.json", "daemonsets.json", "jobs.json"} {
+		require.Contains(t, files, name)
+	}
+	require.Contains(t, string(files["pods.json"]), "release-test-connection")
+	require.Contains(t, string(files["events.json"]), "BackOff")
+	require.Equal(t, "fake logs", string(files["logs/release-pod/init.log"]))
+	require.Equal(t, "fake logs", string(files["logs/release-pod/app.log"]))
+	require.Equal(t, "fake logs", string(files["tests/release-test-connection/wget.log"]))
+	require.NotContains(t, files, "logs/release-test-connection/wget.log")
+	require.NotContains(t, files, "errors.txt")
+}
+
+func TestIsHelmTestPod(t *testing.T) {
+
+	pod := corev1.Pod{}
+	require.False(t, isHelmTestPod(pod))
+	pod.Annotations = map[string]string{helmHookAnnotation: "pre-install"}
+	require.False(t, isHelmTestPod(pod))
+	pod.Annotations[helmHookAnnotation] = "pre-install, test-success"
+	require.True(t, isHelmTestPod(pod))
+}
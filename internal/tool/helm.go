@@ -14,6 +14,7 @@ import (
 	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/cli/values"
 	"helm.sh/helm/v3/pkg/getter"
+	helmrelease "helm.sh/helm/v3/pkg/release"
 	"k8s.io/helm/pkg/strvals"
 )
 
@@ -39,6 +40,11 @@ func NewHelm(envSettings *cli.EnvSettings, args map[string]interface{}, timeout
 	return helm, nil
 }
 
+// Copy returns a new client with the settings of the client, which does not share its action configuration.
+func (h Helm) Copy() (*Helm, error) {
+	return NewHelm(h.envSettings, h.args, h.timeout)
+}
+
 func (h Helm) Install(ctx context.Context, namespace, chart, release, valuesFile string) error {
 	utils.LogInfo(fmt.Sprintf("Execute helm install. namespace: %s, release: %s chart: %s", namespace, release, chart))
 	client := action.NewInstall(h.config)
@@ -55,38 +61,11 @@ func (h Helm) Install(ctx context.Context, namespace, chart, release, valuesFile
 		return err
 	}
 
-	p := getter.All(h.envSettings)
-	valueOpts := &values.Options{}
-	if valuesFile != "" {
-		valueOpts.ValueFiles = append(valueOpts.ValueFiles, valuesFile)
-	}
-	vals, err := valueOpts.MergeValues(p)
+	vals, err := h.getValues(valuesFile)
 	if err != nil {
-		utils.LogError(fmt.Sprintf("Error MergeValues: %v", err))
 		return err
 	}
 
-	if val, ok := h.args["set"]; ok {
-		if err := strvals.ParseInto(fmt.Sprintf("%v", val), vals); err != nil {
-			utils.LogError(fmt.Sprintf("Error parsing --set values: %v", err))
-			return err
-		}
-	}
-
-	if val, ok := h.args["set-file"]; ok {
-		if err := strvals.ParseInto(fmt.Sprintf("%v", val), vals); err != nil {
-			utils.LogError(fmt.Sprintf("Error parsing --set-file values: %v", err))
-			return err
-		}
-	}
-
-	if val, ok := h.args["set-string"]; ok {
-		if err := strvals.ParseInto(fmt.Sprintf("%v", val), vals); err != nil {
-			utils.LogError(fmt.Sprintf("Error parsing --set-string values: %v", err))
-			return err
-		}
-	}
-
 	c, err := loader.Load(cp)
 	if err != nil {
 		utils.LogError(fmt.Sprintf("Error loading chart path: %v", err))
@@ -106,7 +85,9 @@ func (h Helm) Install(ctx context.Context, namespace, chart, release, valuesFile
 	return nil
 }
 
-func (h Helm) Test(ctx context.Context, namespace, release string) error {
+// Test runs the test hooks of a release and returns the release, with the last run of each hook, also when a test hook
+// has failed.
+func (h Helm) Test(ctx context.Context, namespace, release string) (*helmrelease.Release, error) {
 	utils.LogInfo(fmt.Sprintf("Execute helm test. namespace: %s, release: %s, args: %+v", namespace, release, h.args))
 	deadline, _ := ctx.Deadline()
 	client := action.NewReleaseTesting(h.config)
@@ -114,17 +95,30 @@ func (h Helm) Test(ctx context.Context, namespace, release string) error {
 	client.Timeout = deadline.Sub(time.Now())
 
 	if client.Timeout <= 0 {
-		return errors.New("Helm test error : timeout has expired, please consider increasing the timeout using the chart-verifier timeout flag")
+		return nil, errors.New("Helm test error : timeout has expired, please consider increasing the timeout using the chart-verifier timeout flag")
 	}
 	// TODO: support filter
-	_, err := client.Run(release)
+	rel, err := client.Run(release)
 	if err != nil {
 		utils.LogError(fmt.Sprintf("Execute helm test. error %v", err))
-		return err
+		return rel, err
 	}
 
 	utils.LogInfo("Helm test complete")
-	return nil
+	return rel, nil
+}
+
+// GetReleaseObjects returns the objects of the manifest and of the hooks of a release.
+func (h Helm) GetReleaseObjects(namespace, release string) ([]ReleaseObject, error) {
+	rel, err := action.NewGet(h.config).Run(release)
+	if err != nil {
+		return nil, err
+	}
+	manifest := rel.Manifest
+	for _, hook := range rel.Hooks {
+		manifest += "\n---\n" + hook.Manifest
+	}
+	return GetManifestObjects(manifest, namespace)
 }
 
 func (h Helm) Uninstall(namespace, release string) error {
@@ -142,7 +136,46 @@ func (h Helm) Uninstall(namespace, release string) error {
 	return nil
 }
 
-func (h Helm) Upgrade(ctx context.Context, namespace, chart, release string) error {
+// getValues merges the values file, if any, with the set, set-file and set-string args.
+func (h Helm) getValues(valuesFile string) (map[string]interface{}, error) {
+	p := getter.All(h.envSettings)
+	valueOpts := &values.Options{}
+	if valuesFile != "" {
+		valueOpts.ValueFiles = append(valueOpts.ValueFiles, valuesFile)
+	}
+	vals, err := valueOpts.MergeValues(p)
+	if err != nil {
+		utils.LogError(fmt.Sprintf("Error MergeValues: %v", err))
+		return nil, err
+	}
+
+	if val, ok := h.args["set"]; ok {
+		if err := strvals.ParseInto(fmt.Sprintf("%v", val), vals); err != nil {
+			utils.LogError(fmt.Sprintf("Error parsing --set values: %v", err))
+			return nil, err
+		}
+	}
+
+	if val, ok := h.args["set-file"]; ok {
+		if err := strvals.ParseInto(fmt.Sprintf("%v", val), vals); err != nil {
+			utils.LogError(fmt.Sprintf("Error parsing --set-file values: %v", err))
+			return nil, err
+		}
+	}
+
+	if val, ok := h.args["set-string"]; ok {
+		if err := strvals.ParseInto(fmt.Sprintf("%v", val), vals); err != nil {
+			utils.LogError(fmt.Sprintf("Error parsing --set-string values: %v", err))
+			return nil, err
+		}
+	}
+
+	return vals, nil
+}
+
+// Upgrade upgrades a release to the chart with the values of the values file and the set, set-file and set-string
+// args, as Install does, on top of the values of the release.
+func (h Helm) Upgrade(ctx context.Context, namespace, chart, release, valuesFile string) error {
 	utils.LogInfo(fmt.Sprintf("Execute helm upgrade. namespace: %s, release: %s chart: %s", namespace, release, chart))
 	client := action.NewUpgrade(h.config)
 	client.Namespace = namespace
@@ -155,11 +188,8 @@ func (h Helm) Upgrade(ctx context.Context, namespace, chart, release string) err
 		return err
 	}
 
-	p := getter.All(h.envSettings)
-	valueOpts := &values.Options{}
-	vals, err := valueOpts.MergeValues(p)
+	vals, err := h.getValues(valuesFile)
 	if err != nil {
-		utils.LogError(fmt.Sprintf("Error MergeValues: %v", err))
 		return err
 	}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"gopkg.in/yaml.v3"
 	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
@@ -200,7 +201,7 @@ func TestUpgrade(t *testing.T) {
 			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
 			defer cancel()
 			before_upgrade_time := time.Now()
-			err := helm.Upgrade(ctx, "default", tt.chartPath, tt.release.Name)
+			err := helm.Upgrade(ctx, "default", tt.chartPath, tt.release.Name, "")
 			require.WithinDuration(t, before_upgrade_time, time.Now(), tt.timeout)
 			if err == nil {
 				require.Equal(t, tt.expected, "")
@@ -211,6 +212,55 @@ func TestUpgrade(t *testing.T) {
 	}
 }
 
+func TestUpgradeValues(t *testing.T) {
+	testValues, err := chartutil.ReadValuesFile("../chartverifier/checks/psql-service-0.1.7/values.yaml")
+	require.NoError(t, err)
+
+	var chartMetadata chart.Metadata
+	yamlFile, err := ioutil.ReadFile("../chartverifier/checks/psql-service-0.1.7/Chart.yaml")
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(yamlFile, &chartMetadata))
+
+	valuesFile, err := ioutil.TempFile("", "values-*.yaml")
+	require.NoError(t, err)
+	defer os.Remove(valuesFile.Name())
+	_, err = valuesFile.WriteString("k8Project: from-values-file\nreplicaCount: 2\n")
+	require.NoError(t, err)
+	valuesFile.Close()
+
+	store := storage.Init(driver.NewMemory())
+	actionConfig := &action.Configuration{
+		Releases:     store,
+		KubeClient:   &kubefake.PrintingKubeClient{Out: ioutil.Discard},
+		Capabilities: chartutil.DefaultCapabilities,
+		Log:          func(format string, v ...interface{}) {},
+	}
+	helm := Helm{
+		config:      actionConfig,
+		args:        map[string]interface{}{"set": "k8Project=from-set"},
+		envSettings: &cli.EnvSettings{},
+	}
+	rel := &release.Release{
+		Name:      "test-release-values",
+		Info:      &release.Info{Status: release.StatusDeployed},
+		Namespace: "default",
+		Chart:     &chart.Chart{Metadata: &chartMetadata, Values: testValues},
+		Config:    map[string]interface{}{"previous": "kept"},
+		Version:   1,
+	}
+	require.NoError(t, store.Create(rel))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, helm.Upgrade(ctx, "default", "../chartverifier/checks/psql-service-0.1.7", rel.Name, valuesFile.Name()))
+
+	upgraded, err := store.Last(rel.Name)
+	require.NoError(t, err)
+	require.Equal(t, "from-set", upgraded.Config["k8Project"])
+	require.Equal(t, float64(2), upgraded.Config["replicaCount"])
+	require.Equal(t, "kept", upgraded.Config["previous"])
+}
+
 func TestReleaseTesting(t *testing.T) {
 	releaseTestPath := "../chartverifier/checks/psql-service-0.1.7/templates/tests/test-psql-connection.yaml"
 	releaseTest, err := ioutil.ReadFile(releaseTestPath)
@@ -301,7 +351,7 @@ func TestReleaseTesting(t *testing.T) {
 			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
 			defer cancel()
 			before_test_time := time.Now()
-			err := helm.Test(ctx, "default", tt.release.Name)
+			rel, err := helm.Test(ctx, "default", tt.release.Name)
 			if tt.timeout <= 0 {
 				require.WithinDuration(t, before_test_time, time.Now(), 1*time.Second)
 			} else {
@@ -309,6 +359,7 @@ func TestReleaseTesting(t *testing.T) {
 			}
 			if err == nil {
 				require.Equal(t, tt.expected, "")
+				require.Equal(t, tt.release.Name, rel.Name)
 			} else {
 				require.Equal(t, tt.expected, err.Error())
 			}
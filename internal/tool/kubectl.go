@@ -2,80 +2,40 @@ package tool
 
 import (
 	"context"
-	"embed"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
-	"github.com/Masterminds/semver"
-	"gopkg.in/yaml.v3"
 	"helm.sh/helm/v3/pkg/cli"
 	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/kubectl/pkg/scheme"
 
 	"github.com/redhat-certification/chart-verifier/internal/chartverifier/utils"
 )
 
-//go:embed kubeOpenShiftVersionMap.yaml
-var content embed.FS
-
-// Based on https://access.redhat.com/solutions/4870701
-var (
-	kubeOpenShiftVersionMap map[string]string
-	listDeployments         = getDeploymentsList
-	latestKubeVersion       *semver.Version
-)
-
-type versionMap struct {
-	Versions []*versionMapping `yaml:"versions"`
-}
-
-type versionMapping struct {
-	KubeVersion string `yaml:"kube-version"`
-	OcpVersion  string `yaml:"ocp-version"`
-}
+var listDeployments = getDeploymentsList
 
 type deploymentNotReady struct {
 	Name        string
 	Unavailable int32
 }
 
-func init() {
-	kubeOpenShiftVersionMap = make(map[string]string)
-
-	yamlFile, err := content.ReadFile("kubeOpenShiftVersionMap.yaml")
-
-	if err != nil {
-		utils.LogError(fmt.Sprintf("Error reading content of kubeOpenShiftVersionMap.yaml: %v", err))
-		return
-	}
-
-	versions := versionMap{}
-	err = yaml.Unmarshal(yamlFile, &versions)
-	if err != nil {
-		utils.LogError(fmt.Sprintf("Error reading content of kubeOpenShiftVersionMap.yaml: %v", err))
-		return
-	}
-
-	latestKubeVersion, _ = semver.NewVersion("0.0")
-	for _, versionMap := range versions.Versions {
-		currentVersion, _ := semver.NewVersion(versionMap.KubeVersion)
-		if currentVersion.GreaterThan(latestKubeVersion) {
-			latestKubeVersion = currentVersion
-		}
-		kubeOpenShiftVersionMap[versionMap.KubeVersion] = versionMap.OcpVersion
-	}
-
-}
-
 type Kubectl struct {
-	clientset kubernetes.Interface
+	clientset     kubernetes.Interface
+	dynamicClient dynamic.Interface
+	mapper        meta.RESTMapper
 }
 
 func NewKubectl(kubeConfig clientcmd.ClientConfig) (*Kubectl, error) {
@@ -92,6 +52,11 @@ func NewKubectl(kubeConfig clientcmd.ClientConfig) (*Kubectl, error) {
 	if err != nil {
 		return nil, err
 	}
+	kubectl.dynamicClient, err = dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	kubectl.mapper = restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(kubectl.clientset.Discovery()))
 
 	return kubectl, nil
 }
@@ -146,6 +111,30 @@ func (k Kubectl) WaitForDeployments(context context.Context, namespace string, s
 	return nil
 }
 
+// GetPodLogs returns the logs of the containers of a pod, each headed by the name of its container when the pod has
+// more than one.
+func (k Kubectl) GetPodLogs(context context.Context, namespace, name string) (string, error) {
+	pod, err := k.clientset.CoreV1().Pods(namespace).Get(context, name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	var logs strings.Builder
+	for _, container := range pod.Spec.Containers {
+		containerLogs, err := k.clientset.CoreV1().Pods(namespace).GetLogs(name, &corev1.PodLogOptions{Container: container.Name}).DoRaw(context)
+		if err != nil {
+			return "", err
+		}
+		if len(pod.Spec.Containers) > 1 {
+			logs.WriteString(fmt.Sprintf("==> %s <==\n", container.Name))
+		}
+		logs.Write(containerLogs)
+		if len(pod.Spec.Containers) > 1 && !strings.HasSuffix(string(containerLogs), "\n") {
+			logs.WriteString("\n")
+		}
+	}
+	return logs.String(), nil
+}
+
 func (k Kubectl) DeleteNamespace(context context.Context, namespace string) error {
 	if err := k.clientset.CoreV1().Namespaces().Delete(context, namespace, *metav1.NewDeleteOptions(0)); err != nil {
 		return err
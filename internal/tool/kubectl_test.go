@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/version"
 	discoveryfake "k8s.io/client-go/discovery/fake"
@@ -198,3 +199,28 @@ func deploymentTestListBadToGood(k Kubectl, context context.Context, namespace s
 	fmt.Println("deploymentTestListBadToGoodToBad good path")
 	return deploymentTestListGood(k, context, namespace, selector)
 }
+
+func TestGetPodLogs(t *testing.T) {
+
+	namespace := "test-namespace"
+	singleContainer := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "single", Namespace: namespace},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "test"}}},
+	}
+	multiContainer := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "multi", Namespace: namespace},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "first"}, {Name: "second"}}},
+	}
+	kubectl := Kubectl{clientset: fake.NewSimpleClientset(singleContainer, multiContainer)}
+
+	logs, err := kubectl.GetPodLogs(context.Background(), namespace, "single")
+	require.NoError(t, err)
+	require.Equal(t, "fake logs", logs)
+
+	logs, err = kubectl.GetPodLogs(context.Background(), namespace, "multi")
+	require.NoError(t, err)
+	require.Equal(t, "==> first <==\nfake logs\n==> second <==\nfake logs\n", logs)
+
+	_, err = kubectl.GetPodLogs(context.Background(), namespace, "missing")
+	require.Error(t, err)
+}
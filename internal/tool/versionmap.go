@@ -0,0 +1,203 @@
+package tool
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"gopkg.in/yaml.v3"
+
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier/utils"
+)
+
+const (
+	embeddedVersionMapName = "kubeOpenShiftVersionMap.yaml"
+	versionMapReadTimeout  = 30 * time.Second
+)
+
+//go:embed kubeOpenShiftVersionMap.yaml
+var content embed.FS
+
+// Based on https://access.redhat.com/solutions/4870701
+var (
+	kubeOpenShiftVersionMap map[string]string
+	kindNodeImageMap        map[string]string
+	latestKubeVersion       *semver.Version
+	versionMapVersion       string
+	// loadedVersionMapURI is the file or url the map in use was loaded from, empty for the embedded map.
+	loadedVersionMapURI string
+	versionMapLoaded    bool
+	// versionMapMutex serialises the loading of the map, charts being verified concurrently.
+	versionMapMutex sync.Mutex
+)
+
+type versionMap struct {
+	// Version identifies the map, it is recorded in the report.
+	Version  string            `yaml:"version"`
+	Versions []*versionMapping `yaml:"versions"`
+}
+
+type versionMapping struct {
+	KubeVersion   string `yaml:"kube-version"`
+	OcpVersion    string `yaml:"ocp-version"`
+	KindNodeImage string `yaml:"kind-node-image,omitempty"`
+}
+
+// ReleaseMetadata lists OpenShift releases with the Kubernetes version each of them runs, the source a version map is
+// generated from.
+type ReleaseMetadata struct {
+	Releases []Release `yaml:"releases"`
+}
+
+type Release struct {
+	OpenShiftVersion string `yaml:"openshift-version"`
+	KubeVersion      string `yaml:"kube-version"`
+}
+
+func init() {
+	if err := LoadVersionMap(""); err != nil {
+		utils.LogError(err.Error())
+	}
+}
+
+// LoadVersionMap replaces the Kubernetes to OpenShift version map with the map of a file or url, or with the map
+// embedded in the verifier if the uri is empty. The map is not read again if it is already loaded from the uri.
+func LoadVersionMap(uri string) error {
+	versionMapMutex.Lock()
+	defer versionMapMutex.Unlock()
+	if versionMapLoaded && uri == loadedVersionMapURI {
+		return nil
+	}
+
+	source := uri
+	var mapContent []byte
+	var err error
+	if len(uri) == 0 {
+		source = embeddedVersionMapName
+		mapContent, err = content.ReadFile(embeddedVersionMapName)
+	} else {
+		mapContent, err = ReadURI(uri)
+	}
+	if err != nil {
+		return fmt.Errorf("error reading version map %s: %v", source, err)
+	}
+
+	versions := versionMap{}
+	if err = yaml.Unmarshal(mapContent, &versions); err != nil {
+		return fmt.Errorf("error reading version map %s: %v", source, err)
+	}
+	if len(versions.Versions) == 0 {
+		return fmt.Errorf("version map %s has no versions", source)
+	}
+
+	kubeVersions := make(map[string]string)
+	kindNodeImages := make(map[string]string)
+	latest, _ := semver.NewVersion("0.0")
+	for _, versionMap := range versions.Versions {
+		currentVersion, err := semver.NewVersion(versionMap.KubeVersion)
+		if err != nil {
+			return fmt.Errorf("version map %s has an invalid Kubernetes version %q: %v", source, versionMap.KubeVersion, err)
+		}
+		if currentVersion.GreaterThan(latest) {
+			latest = currentVersion
+		}
+		kubeVersions[versionMap.KubeVersion] = versionMap.OcpVersion
+		if len(versionMap.KindNodeImage) > 0 {
+			kindNodeImages[versionMap.KubeVersion] = versionMap.KindNodeImage
+		}
+	}
+
+	kubeOpenShiftVersionMap = kubeVersions
+	kindNodeImageMap = kindNodeImages
+	latestKubeVersion = latest
+	versionMapVersion = versions.Version
+	loadedVersionMapURI = uri
+	versionMapLoaded = true
+	if len(uri) > 0 {
+		utils.LogInfo(fmt.Sprintf("Version map %s loaded from %s", versionMapVersion, source))
+	}
+	return nil
+}
+
+// GetVersionMapVersion returns the version of the Kubernetes to OpenShift version map in use.
+func GetVersionMapVersion() string {
+	return versionMapVersion
+}
+
+// GenerateVersionMap generates a Kubernetes to OpenShift version map from release metadata. Each Kubernetes minor
+// version is mapped to the lowest OpenShift minor version running it, and keeps the kind node image of the map in use.
+func GenerateVersionMap(metadata []byte, mapVersion string) ([]byte, error) {
+	releases := ReleaseMetadata{}
+	if err := yaml.Unmarshal(metadata, &releases); err != nil {
+		return nil, fmt.Errorf("error reading release metadata: %v", err)
+	}
+	if len(releases.Releases) == 0 {
+		return nil, fmt.Errorf("release metadata has no releases")
+	}
+
+	ocpVersions := make(map[string]*semver.Version)
+	for _, release := range releases.Releases {
+		ocpVersion, err := semver.NewVersion(release.OpenShiftVersion)
+		if err != nil {
+			return nil, fmt.Errorf("release metadata has an invalid OpenShift version %q: %v", release.OpenShiftVersion, err)
+		}
+		kubeVersion, err := semver.NewVersion(release.KubeVersion)
+		if err != nil {
+			return nil, fmt.Errorf("release metadata has an invalid Kubernetes version %q for OpenShift %s: %v", release.KubeVersion, release.OpenShiftVersion, err)
+		}
+		kubeMinor := fmt.Sprintf("%d.%d", kubeVersion.Major(), kubeVersion.Minor())
+		if current, ok := ocpVersions[kubeMinor]; !ok || ocpVersion.LessThan(current) {
+			ocpVersions[kubeMinor] = ocpVersion
+		}
+	}
+
+	versions := versionMap{Version: mapVersion}
+	for kubeMinor, ocpVersion := range ocpVersions {
+		versions.Versions = append(versions.Versions, &versionMapping{
+			KubeVersion:   kubeMinor,
+			OcpVersion:    fmt.Sprintf("%d.%d", ocpVersion.Major(), ocpVersion.Minor()),
+			KindNodeImage: kindNodeImageMap[kubeMinor],
+		})
+	}
+	// newest first, as in the embedded map.
+	sort.Slice(versions.Versions, func(i, j int) bool {
+		return semver.MustParse(versions.Versions[i].KubeVersion).GreaterThan(semver.MustParse(versions.Versions[j].KubeVersion))
+	})
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(4)
+	if err := encoder.Encode(&versions); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// ReadURI reads the content of an http or https url, or of a file.
+func ReadURI(uri string) ([]byte, error) {
+	if u, err := url.Parse(uri); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		client := http.Client{Timeout: versionMapReadTimeout}
+		// #nosec G107
+		resp, err := client.Get(uri)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("response code %d from %s", resp.StatusCode, uri)
+		}
+		return ioutil.ReadAll(resp.Body)
+	}
+	// #nosec G304
+	return ioutil.ReadFile(uri)
+}
@@ -0,0 +1,94 @@
+package tool
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const releaseMetadata = `releases:
+  - openshift-version: 4.12.1
+    kube-version: v1.25.4
+  - openshift-version: 4.12.0
+    kube-version: v1.25.2
+  - openshift-version: 4.11.20
+    kube-version: v1.24.6
+  - openshift-version: 4.10.45
+    kube-version: v1.23.12
+  - openshift-version: 4.9.54
+    kube-version: v1.22.8
+  - openshift-version: 4.8.55
+    kube-version: v1.21.14
+  - openshift-version: 4.7.60
+    kube-version: v1.20.15
+  - openshift-version: 4.6.62
+    kube-version: v1.19.16
+  - openshift-version: 4.5.41
+    kube-version: v1.18.3
+  - openshift-version: 4.4.33
+    kube-version: v1.17.1
+  - openshift-version: 4.3.40
+    kube-version: v1.16.2
+  - openshift-version: 4.2.36
+    kube-version: v1.14.6
+  - openshift-version: 4.1.41
+    kube-version: v1.13.4
+`
+
+func writeTempFile(t *testing.T, name, content string) string {
+	fileName := path.Join(t.TempDir(), name)
+	require.NoError(t, ioutil.WriteFile(fileName, []byte(content), 0600))
+	return fileName
+}
+
+func TestLoadVersionMap(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, LoadVersionMap("")) })
+
+	require.Equal(t, "2022.11.01", GetVersionMapVersion())
+	require.Equal(t, "4.12", GetKubeOpenShiftVersionMap()["1.25"])
+
+	versionMap := writeTempFile(t, "versions.yaml", `version: "2023.02.01"
+versions:
+    - kube-version: "1.26"
+      ocp-version: "4.13"
+      kind-node-image: "kindest/node:v1.26.0"
+    - kube-version: "1.25"
+      ocp-version: "4.12"
+`)
+	require.NoError(t, LoadVersionMap(versionMap))
+	require.Equal(t, "2023.02.01", GetVersionMapVersion())
+	require.Equal(t, map[string]string{"1.26": "4.13", "1.25": "4.12"}, GetKubeOpenShiftVersionMap())
+	require.Equal(t, "1.26.0", GetLatestKubeVersion())
+	kubeVersion, err := GetEphemeralKubeVersion("4.13")
+	require.NoError(t, err)
+	require.Equal(t, "1.26", kubeVersion)
+
+	require.Error(t, LoadVersionMap(writeTempFile(t, "empty.yaml", "version: \"2023.03.01\"\n")))
+	require.Error(t, LoadVersionMap(path.Join(t.TempDir(), "missing.yaml")))
+	require.Equal(t, "2023.02.01", GetVersionMapVersion())
+
+	require.NoError(t, LoadVersionMap(""))
+	require.Equal(t, "2022.11.01", GetVersionMapVersion())
+}
+
+func TestGenerateVersionMap(t *testing.T) {
+	t.Cleanup(func() { require.NoError(t, LoadVersionMap("")) })
+
+	embeddedVersions := GetKubeOpenShiftVersionMap()
+	embeddedNodeImages := kindNodeImageMap
+
+	generated, err := GenerateVersionMap([]byte(releaseMetadata), "2023.01.15")
+	require.NoError(t, err)
+
+	require.NoError(t, LoadVersionMap(writeTempFile(t, "versions.yaml", string(generated))))
+	require.Equal(t, "2023.01.15", GetVersionMapVersion())
+	require.Equal(t, embeddedVersions, GetKubeOpenShiftVersionMap())
+	require.Equal(t, embeddedNodeImages, kindNodeImageMap)
+
+	_, err = GenerateVersionMap([]byte("releases: []"), "2023.01.15")
+	require.EqualError(t, err, "release metadata has no releases")
+	_, err = GenerateVersionMap([]byte("releases:\n  - openshift-version: 4.12.0\n    kube-version: latest\n"), "2023.01.15")
+	require.Error(t, err)
+}
@@ -0,0 +1,360 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier/utils"
+)
+
+const (
+	waitInitialBackoff = time.Second
+	waitMaxBackoff     = 16 * time.Second
+)
+
+// listWorkloadsNotReady is a variable so the listing of workloads can be replaced in tests.
+var listWorkloadsNotReady = getWorkloadsNotReady
+
+// customResourceDefinitions is the resource of the definitions of custom resources.
+var customResourceDefinitions = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// workloadNotReady is a release object which is not ready yet, and why.
+type workloadNotReady struct {
+	Kind   string
+	Name   string
+	Reason string
+	// Failed is set when the object will not become ready, for example a job which has failed.
+	Failed bool
+}
+
+func (w workloadNotReady) String() string {
+	return fmt.Sprintf("%s/%s: %s", w.Kind, w.Name, w.Reason)
+}
+
+// WaitForWorkloads waits for the deployments, statefulsets, daemonsets, jobs, persistent volume claims and pods of a
+// namespace matching the selector, and for the custom resources of the release objects, to be ready, backing off between
+// polls. If the wait times out, or an object fails, the error reports the objects which are not ready.
+func (k Kubectl) WaitForWorkloads(context context.Context, namespace string, selector string, objects []ReleaseObject) error {
+	deadline, _ := context.Deadline()
+	var notReady []workloadNotReady
+	getWorkloadsError := ""
+	backoff := waitInitialBackoff
+
+	utils.LogInfo(fmt.Sprintf("Start wait for workloads. --timeout time left: %s ", deadline.Sub(time.Now()).String()))
+
+	for {
+		workloads, err := listWorkloadsNotReady(k, context, namespace, selector, objects)
+		if err != nil {
+			getWorkloadsError = fmt.Sprintf("error getting workloads from namespace %s : %v", namespace, err)
+			utils.LogWarning(getWorkloadsError)
+		} else {
+			getWorkloadsError = ""
+			notReady = workloads
+			if len(notReady) == 0 {
+				utils.LogInfo(fmt.Sprintf("Finish wait for workloads, --timeout time left %s", deadline.Sub(time.Now()).String()))
+				return nil
+			}
+			if failed := getFailedWorkloads(notReady); len(failed) > 0 {
+				errorMsg := fmt.Sprintf("Error workloads failed: %s", joinWorkloads(failed))
+				utils.LogError(errorMsg)
+				return errors.New(errorMsg)
+			}
+			utils.LogInfo(fmt.Sprintf("Wait for %d workloads:", len(notReady)))
+			for _, workload := range notReady {
+				utils.LogInfo(fmt.Sprintf("    - %s", workload))
+			}
+		}
+
+		select {
+		case <-context.Done():
+			if len(getWorkloadsError) > 0 {
+				errorMsg := fmt.Sprintf("Time out retrying after %s", getWorkloadsError)
+				utils.LogError(errorMsg)
+				return errors.New(errorMsg)
+			}
+			errorMsg := fmt.Sprintf("Error workloads not ready, timeout has expired, please consider increasing the timeout using the chart-verifier --timeout flag: %s", joinWorkloads(notReady))
+			utils.LogError(errorMsg)
+			return errors.New(errorMsg)
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > waitMaxBackoff {
+			backoff = waitMaxBackoff
+		}
+	}
+}
+
+func getFailedWorkloads(workloads []workloadNotReady) []workloadNotReady {
+	failed := make([]workloadNotReady, 0)
+	for _, workload := range workloads {
+		if workload.Failed {
+			failed = append(failed, workload)
+		}
+	}
+	return failed
+}
+
+func joinWorkloads(workloads []workloadNotReady) string {
+	names := make([]string, 0, len(workloads))
+	for _, workload := range workloads {
+		names = append(names, workload.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+// getWorkloadsNotReady returns the objects of the namespace matching the selector, and the custom resources of the
+// release objects, which are not ready.
+func getWorkloadsNotReady(k Kubectl, context context.Context, namespace string, selector string, objects []ReleaseObject) ([]workloadNotReady, error) {
+	listOptions := metav1.ListOptions{LabelSelector: selector}
+	notReady := make([]workloadNotReady, 0)
+
+	deployments, err := k.clientset.AppsV1().Deployments(namespace).List(context, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	for _, deployment := range deployments.Items {
+		if reason := getDeploymentNotReadyReason(deployment); len(reason) > 0 {
+			notReady = append(notReady, workloadNotReady{Kind: "Deployment", Name: deployment.Name, Reason: reason})
+		}
+	}
+
+	statefulSets, err := k.clientset.AppsV1().StatefulSets(namespace).List(context, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	for _, statefulSet := range statefulSets.Items {
+		if reason := getStatefulSetNotReadyReason(statefulSet); len(reason) > 0 {
+			notReady = append(notReady, workloadNotReady{Kind: "StatefulSet", Name: statefulSet.Name, Reason: reason})
+		}
+	}
+
+	daemonSets, err := k.clientset.AppsV1().DaemonSets(namespace).List(context, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	for _, daemonSet := range daemonSets.Items {
+		if reason := getDaemonSetNotReadyReason(daemonSet); len(reason) > 0 {
+			notReady = append(notReady, workloadNotReady{Kind: "DaemonSet", Name: daemonSet.Name, Reason: reason})
+		}
+	}
+
+	jobs, err := k.clientset.BatchV1().Jobs(namespace).List(context, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs.Items {
+		if reason, failed := getJobNotReadyReason(job); len(reason) > 0 {
+			notReady = append(notReady, workloadNotReady{Kind: "Job", Name: job.Name, Reason: reason, Failed: failed})
+		}
+	}
+
+	pvcs, err := k.clientset.CoreV1().PersistentVolumeClaims(namespace).List(context, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	for _, pvc := range pvcs.Items {
+		if pvc.Status.Phase != corev1.ClaimBound {
+			notReady = append(notReady, workloadNotReady{Kind: "PersistentVolumeClaim", Name: pvc.Name, Reason: fmt.Sprintf("not bound (%s)", pvc.Status.Phase)})
+		}
+	}
+
+	pods, err := k.clientset.CoreV1().Pods(namespace).List(context, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		if reason := getPodNotReadyReason(pod); len(reason) > 0 {
+			notReady = append(notReady, workloadNotReady{Kind: "Pod", Name: pod.Name, Reason: reason})
+		}
+	}
+
+	customResources, err := k.getCustomResourcesNotReady(context, objects)
+	if err != nil {
+		return nil, err
+	}
+	notReady = append(notReady, customResources...)
+
+	return notReady, nil
+}
+
+// getCustomResourcesNotReady returns the release objects which are custom resources and are not ready. Objects which
+// are not found, such as hooks which have not run, are not waited for.
+func (k Kubectl) getCustomResourcesNotReady(context context.Context, objects []ReleaseObject) ([]workloadNotReady, error) {
+	notReady := make([]workloadNotReady, 0)
+	customResources := make(map[schema.GroupResource]bool)
+	for _, object := range objects {
+		gvk := schema.FromAPIVersionAndKind(object.APIVersion, object.Kind)
+		if len(gvk.Group) == 0 || k.dynamicClient == nil || k.mapper == nil {
+			continue
+		}
+		mapping, err := k.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, err
+		}
+		groupResource := mapping.Resource.GroupResource()
+		isCustomResource, found := customResources[groupResource]
+		if !found {
+			if isCustomResource, err = k.isCustomResource(context, groupResource); err != nil {
+				return nil, err
+			}
+			customResources[groupResource] = isCustomResource
+		}
+		if !isCustomResource {
+			continue
+		}
+
+		resource := k.dynamicClient.Resource(mapping.Resource)
+		var customResource *unstructured.Unstructured
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			customResource, err = resource.Namespace(object.Namespace).Get(context, object.Name, metav1.GetOptions{})
+		} else {
+			customResource, err = resource.Get(context, object.Name, metav1.GetOptions{})
+		}
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if reason := getCustomResourceNotReadyReason(customResource); len(reason) > 0 {
+			notReady = append(notReady, workloadNotReady{Kind: object.Kind, Name: object.Name, Reason: reason})
+		}
+	}
+	return notReady, nil
+}
+
+// isCustomResource returns whether a resource is defined by a custom resource definition.
+func (k Kubectl) isCustomResource(context context.Context, groupResource schema.GroupResource) (bool, error) {
+	_, err := k.dynamicClient.Resource(customResourceDefinitions).Get(context, groupResource.String(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// getCustomResourceNotReadyReason returns why a custom resource is not ready, from its Ready and Available conditions.
+// A custom resource with neither condition is not waited for, as whether it is ready is not known.
+func getCustomResourceNotReadyReason(customResource *unstructured.Unstructured) string {
+	conditions, _, _ := unstructured.NestedSlice(customResource.Object, "status", "conditions")
+	for _, value := range conditions {
+		condition, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditionType, _ := condition["type"].(string)
+		status, _ := condition["status"].(string)
+		if (conditionType != "Ready" && conditionType != "Available") || status == string(metav1.ConditionTrue) {
+			continue
+		}
+		if message, _ := condition["message"].(string); len(message) > 0 {
+			return fmt.Sprintf("%s condition is %s: %s", conditionType, status, message)
+		}
+		return fmt.Sprintf("%s condition is %s", conditionType, status)
+	}
+	return ""
+}
+
+func getReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+func getDeploymentNotReadyReason(deployment v1.Deployment) string {
+	replicas := getReplicas(deployment.Spec.Replicas)
+	switch {
+	case deployment.Status.ObservedGeneration < deployment.Generation:
+		return "rollout not yet observed"
+	case deployment.Status.UpdatedReplicas < replicas:
+		return fmt.Sprintf("%d of %d replicas updated", deployment.Status.UpdatedReplicas, replicas)
+	case deployment.Status.UnavailableReplicas > 0:
+		// Just after rollout, pods from the previous deployment revision may still be in a terminating state.
+		return fmt.Sprintf("%d unavailable replicas", deployment.Status.UnavailableReplicas)
+	}
+	return ""
+}
+
+func getStatefulSetNotReadyReason(statefulSet v1.StatefulSet) string {
+	replicas := getReplicas(statefulSet.Spec.Replicas)
+	updated := replicas
+	if rollingUpdate := statefulSet.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil && rollingUpdate.Partition != nil {
+		updated -= *rollingUpdate.Partition
+	}
+	switch {
+	case statefulSet.Status.ObservedGeneration < statefulSet.Generation:
+		return "rollout not yet observed"
+	case statefulSet.Spec.UpdateStrategy.Type != v1.OnDeleteStatefulSetStrategyType && statefulSet.Status.UpdatedReplicas < updated:
+		return fmt.Sprintf("%d of %d replicas updated", statefulSet.Status.UpdatedReplicas, updated)
+	case statefulSet.Status.ReadyReplicas < replicas:
+		return fmt.Sprintf("%d of %d replicas ready", statefulSet.Status.ReadyReplicas, replicas)
+	}
+	return ""
+}
+
+func getDaemonSetNotReadyReason(daemonSet v1.DaemonSet) string {
+	desired := daemonSet.Status.DesiredNumberScheduled
+	switch {
+	case daemonSet.Status.ObservedGeneration < daemonSet.Generation:
+		return "rollout not yet observed"
+	case daemonSet.Spec.UpdateStrategy.Type != v1.OnDeleteDaemonSetStrategyType && daemonSet.Status.UpdatedNumberScheduled < desired:
+		return fmt.Sprintf("%d of %d pods updated", daemonSet.Status.UpdatedNumberScheduled, desired)
+	case daemonSet.Status.NumberReady < desired:
+		return fmt.Sprintf("%d of %d pods ready", daemonSet.Status.NumberReady, desired)
+	}
+	return ""
+}
+
+// getJobNotReadyReason returns why a job is not complete, and whether it has failed.
+func getJobNotReadyReason(job batchv1.Job) (string, bool) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return "", false
+		case batchv1.JobFailed:
+			return fmt.Sprintf("failed: %s", condition.Message), true
+		}
+	}
+	return fmt.Sprintf("not complete, %d active, %d succeeded", job.Status.Active, job.Status.Succeeded), false
+}
+
+// getPodNotReadyReason returns why a pod is not ready. Pods run by jobs, completed pods, terminating pods and helm test
+// pods are not waited for.
+func getPodNotReadyReason(pod corev1.Pod) string {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || isHelmTestPod(pod) {
+		return ""
+	}
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "Job" {
+			return ""
+		}
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+			return ""
+		}
+	}
+	waiting := make([]string, 0)
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting != nil && len(status.State.Waiting.Reason) > 0 {
+			waiting = append(waiting, fmt.Sprintf("%s %s", status.Name, status.State.Waiting.Reason))
+		}
+	}
+	if len(waiting) > 0 {
+		return fmt.Sprintf("not ready (%s)", strings.Join(waiting, ", "))
+	}
+	return fmt.Sprintf("not ready (%s)", pod.Status.Phase)
+}
@@ -0,0 +1,222 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var releaseLabels = map[string]string{"app.kubernetes.io/instance": "release"}
+
+const releaseSelector = "app.kubernetes.io/instance=release"
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func readyPod(name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test", Labels: releaseLabels},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func TestGetWorkloadsNotReady(t *testing.T) {
+
+	clientset := fake.NewSimpleClientset(
+		&v1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test", Labels: releaseLabels},
+			Spec:       v1.DeploymentSpec{Replicas: int32Ptr(2)},
+			Status:     v1.DeploymentStatus{UpdatedReplicas: 2, UnavailableReplicas: 1},
+		},
+		&v1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "test", Labels: releaseLabels},
+			Spec:       v1.StatefulSetSpec{Replicas: int32Ptr(3)},
+			Status:     v1.StatefulSetStatus{UpdatedReplicas: 3, ReadyReplicas: 1},
+		},
+		&v1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "test", Labels: releaseLabels},
+			Status:     v1.DaemonSetStatus{DesiredNumberScheduled: 2, UpdatedNumberScheduled: 2, NumberReady: 2},
+		},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "test", Labels: releaseLabels},
+			Status:     batchv1.JobStatus{Active: 1},
+		},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "test", Labels: releaseLabels},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+		},
+		readyPod("web-1"),
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "test", Labels: releaseLabels},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "web", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+				},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "test"},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		},
+	)
+	k := Kubectl{clientset: clientset}
+
+	notReady, err := getWorkloadsNotReady(k, context.Background(), "test", releaseSelector, nil)
+	require.NoError(t, err)
+	require.Equal(t, []workloadNotReady{
+		{Kind: "Deployment", Name: "web", Reason: "1 unavailable replicas"},
+		{Kind: "StatefulSet", Name: "db", Reason: "1 of 3 replicas ready"},
+		{Kind: "Job", Name: "migrate", Reason: "not complete, 1 active, 0 succeeded"},
+		{Kind: "PersistentVolumeClaim", Name: "data", Reason: "not bound (Pending)"},
+		{Kind: "Pod", Name: "web-2", Reason: "not ready (web ImagePullBackOff)"},
+	}, notReady)
+}
+
+func TestGetNotReadyReasons(t *testing.T) {
+
+	require.Equal(t, "rollout not yet observed", getDeploymentNotReadyReason(v1.Deployment{ObjectMeta: metav1.ObjectMeta{Generation: 2}, Status: v1.DeploymentStatus{ObservedGeneration: 1}}))
+	require.Equal(t, "0 of 1 replicas updated", getDeploymentNotReadyReason(v1.Deployment{}))
+	require.Empty(t, getDeploymentNotReadyReason(v1.Deployment{Status: v1.DeploymentStatus{UpdatedReplicas: 1}}))
+
+	partitioned := v1.StatefulSet{
+		Spec: v1.StatefulSetSpec{
+			Replicas:       int32Ptr(3),
+			UpdateStrategy: v1.StatefulSetUpdateStrategy{Type: v1.RollingUpdateStatefulSetStrategyType, RollingUpdate: &v1.RollingUpdateStatefulSetStrategy{Partition: int32Ptr(2)}},
+		},
+		Status: v1.StatefulSetStatus{UpdatedReplicas: 1, ReadyReplicas: 3},
+	}
+	require.Empty(t, getStatefulSetNotReadyReason(partitioned))
+
+	require.Equal(t, "1 of 2 pods updated", getDaemonSetNotReadyReason(v1.DaemonSet{Status: v1.DaemonSetStatus{DesiredNumberScheduled: 2, UpdatedNumberScheduled: 1}}))
+
+	reason, failed := getJobNotReadyReason(batchv1.Job{Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}}})
+	require.Empty(t, reason)
+	require.False(t, failed)
+	reason, failed = getJobNotReadyReason(batchv1.Job{Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}}}})
+	require.Equal(t, "failed: BackoffLimitExceeded", reason)
+	require.True(t, failed)
+
+	jobPod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{Kind: "Job", Name: "migrate"}}}}
+	require.Empty(t, getPodNotReadyReason(jobPod))
+	testPod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{helmHookAnnotation: "test"}}}
+	require.Empty(t, getPodNotReadyReason(testPod))
+	require.Empty(t, getPodNotReadyReason(corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodSucceeded}}))
+}
+
+func TestWaitForWorkloads(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	k := Kubectl{clientset: fake.NewSimpleClientset(readyPod("web-1"))}
+	require.NoError(t, k.WaitForWorkloads(ctx, "test", releaseSelector, nil))
+}
+
+func TestTimeExpirationWaitingForWorkloads(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	k := Kubectl{clientset: fake.NewSimpleClientset(&v1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "test", Labels: releaseLabels},
+		Status:     v1.StatefulSetStatus{UpdatedReplicas: 1},
+	})}
+	err := k.WaitForWorkloads(ctx, "test", releaseSelector, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Error workloads not ready, timeout has expired,")
+	require.Contains(t, err.Error(), "StatefulSet/db: 0 of 1 replicas ready")
+}
+
+func TestFailedJobWaitingForWorkloads(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	k := Kubectl{clientset: fake.NewSimpleClientset(&batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "test", Labels: releaseLabels},
+		Status:     batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}}},
+	})}
+	before := time.Now()
+	err := k.WaitForWorkloads(ctx, "test", releaseSelector, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Error workloads failed: Job/migrate: failed: BackoffLimitExceeded")
+	require.WithinDuration(t, before, time.Now(), time.Second)
+}
+
+func TestTimeExpirationGetWorkloadsFailure(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	listWorkloadsNotReady = func(k Kubectl, context context.Context, namespace string, selector string, objects []ReleaseObject) ([]workloadNotReady, error) {
+		return nil, errors.New("pretend error getting workloads")
+	}
+	defer func() { listWorkloadsNotReady = getWorkloadsNotReady }()
+
+	k := new(Kubectl)
+	err := k.WaitForWorkloads(ctx, "test", releaseSelector, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Time out retrying after error getting workloads from namespace test")
+	require.Contains(t, err.Error(), "pretend error getting workloads")
+}
+
+func newWidget(name string, conditions ...interface{}) *unstructured.Unstructured {
+	widget := newUnstructured("example.com/v1", "Widget", "test", name)
+	if len(conditions) > 0 {
+		_ = unstructured.SetNestedSlice(widget.Object, conditions, "status", "conditions")
+	}
+	return widget
+}
+
+func TestGetCustomResourcesNotReady(t *testing.T) {
+
+	k, _ := newCleanupKubectl(
+		newUnstructured("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", "widgets.example.com"),
+		newWidget("not-ready", map[string]interface{}{"type": "Ready", "status": "False", "message": "waiting for the database"}),
+		newWidget("not-available", map[string]interface{}{"type": "Ready", "status": "True"}, map[string]interface{}{"type": "Available", "status": "Unknown"}),
+		newWidget("available", map[string]interface{}{"type": "Available", "status": "True"}),
+		newWidget("unknown"),
+	)
+	objects := []ReleaseObject{
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "test", Name: "chart-config"},
+		{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "chart-reader"},
+		{APIVersion: "example.com/v1", Kind: "Widget", Namespace: "test", Name: "not-ready"},
+		{APIVersion: "example.com/v1", Kind: "Widget", Namespace: "test", Name: "not-available"},
+		{APIVersion: "example.com/v1", Kind: "Widget", Namespace: "test", Name: "available"},
+		{APIVersion: "example.com/v1", Kind: "Widget", Namespace: "test", Name: "unknown"},
+		{APIVersion: "example.com/v1", Kind: "Widget", Namespace: "test", Name: "missing"},
+	}
+
+	notReady, err := k.getCustomResourcesNotReady(context.Background(), objects)
+	require.NoError(t, err)
+	require.Equal(t, []workloadNotReady{
+		{Kind: "Widget", Name: "not-ready", Reason: "Ready condition is False: waiting for the database"},
+		{Kind: "Widget", Name: "not-available", Reason: "Available condition is Unknown"},
+	}, notReady)
+}
+
+func TestTimeExpirationWaitingForCustomResources(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	k, _ := newCleanupKubectl(
+		newUnstructured("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", "widgets.example.com"),
+		newWidget("chart-widget", map[string]interface{}{"type": "Ready", "status": "False"}),
+	)
+	k.clientset = fake.NewSimpleClientset()
+	err := k.WaitForWorkloads(ctx, "test", releaseSelector, []ReleaseObject{{APIVersion: "example.com/v1", Kind: "Widget", Namespace: "test", Name: "chart-widget"}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Error workloads not ready, timeout has expired,")
+	require.Contains(t, err.Error(), "Widget/chart-widget: Ready condition is False")
+}
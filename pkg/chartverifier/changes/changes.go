@@ -0,0 +1,199 @@
+// Package changes selects the charts of a git working tree which changed since a git ref.
+package changes
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"helm.sh/helm/v3/pkg/chartutil"
+
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier"
+)
+
+const fileRepositoryPrefix = "file://"
+
+// GetChangedCharts returns the chart directories of a git working tree which hold a file changed since a git ref, or
+// which depend on such a chart through a file:// dependency. The changes are those committed since the merge base of
+// the ref and HEAD, as in a pull request, and those not committed yet.
+func GetChangedCharts(repoDir string, sinceRef string) ([]string, error) {
+
+	repo, err := git.PlainOpenWithOptions(repoDir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("error opening git repository %s: %v", repoDir, err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("error opening git working tree %s: %v", repoDir, err)
+	}
+	root, err := getRealPath(worktree.Filesystem.Root())
+	if err != nil {
+		return nil, err
+	}
+
+	changedFiles, err := getChangedFiles(repo, worktree, sinceRef)
+	if err != nil {
+		return nil, err
+	}
+
+	charts, err := chartverifier.DiscoverCharts(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	chartDirs := make(map[string]string)
+	for _, chart := range charts {
+		// packaged charts are selected when the archive itself changed.
+		chartDir, err := getRealPath(chart)
+		if err != nil {
+			return nil, err
+		}
+		chartDirs[chartDir] = chart
+	}
+
+	changed := make(map[string]bool)
+	for _, file := range changedFiles {
+		if chartDir := getChartDir(filepath.Join(root, filepath.FromSlash(file)), chartDirs); len(chartDir) > 0 {
+			changed[chartDir] = true
+		}
+	}
+
+	dependencies := getLocalDependencies(chartDirs)
+	// a chart changes when a chart it depends on changes, through any number of dependencies.
+	for added := true; added; {
+		added = false
+		for chartDir, chartDependencies := range dependencies {
+			if changed[chartDir] {
+				continue
+			}
+			for _, dependency := range chartDependencies {
+				if changed[dependency] {
+					changed[chartDir] = true
+					added = true
+					break
+				}
+			}
+		}
+	}
+
+	changedCharts := make([]string, 0, len(changed))
+	for chartDir := range changed {
+		changedCharts = append(changedCharts, chartDirs[chartDir])
+	}
+	sort.Strings(changedCharts)
+	return changedCharts, nil
+}
+
+// getChangedFiles returns the path, relative to the root of the working tree, of each file changed since the merge
+// base of the ref and HEAD, committed or not.
+func getChangedFiles(repo *git.Repository, worktree *git.Worktree, sinceRef string) ([]string, error) {
+
+	sinceHash, err := repo.ResolveRevision(plumbing.Revision(sinceRef))
+	if err != nil {
+		return nil, fmt.Errorf("error resolving git ref %s: %v", sinceRef, err)
+	}
+	sinceCommit, err := repo.CommitObject(*sinceHash)
+	if err != nil {
+		return nil, fmt.Errorf("error reading commit of git ref %s: %v", sinceRef, err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("error resolving git HEAD: %v", err)
+	}
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("error reading commit of git HEAD: %v", err)
+	}
+
+	if bases, err := sinceCommit.MergeBase(headCommit); err == nil && len(bases) > 0 {
+		sinceCommit = bases[0]
+	}
+	sinceTree, err := sinceCommit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("error reading tree of git ref %s: %v", sinceRef, err)
+	}
+	headTree, err := headCommit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("error reading tree of git HEAD: %v", err)
+	}
+	treeChanges, err := object.DiffTree(sinceTree, headTree)
+	if err != nil {
+		return nil, fmt.Errorf("error comparing git ref %s with HEAD: %v", sinceRef, err)
+	}
+
+	files := make([]string, 0, len(treeChanges))
+	for _, change := range treeChanges {
+		// renamed files change both the chart they leave and the chart they join.
+		for _, name := range []string{change.From.Name, change.To.Name} {
+			if len(name) > 0 {
+				files = append(files, name)
+			}
+		}
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, fmt.Errorf("error reading git status: %v", err)
+	}
+	for file, fileStatus := range status {
+		if fileStatus.Staging != git.Unmodified || fileStatus.Worktree != git.Unmodified {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// getChartDir returns the chart directory holding a file, the innermost one if charts are nested.
+func getChartDir(file string, chartDirs map[string]string) string {
+	chartDir := ""
+	for dir := range chartDirs {
+		if (file == dir || strings.HasPrefix(file, dir+string(filepath.Separator))) && len(dir) > len(chartDir) {
+			chartDir = dir
+		}
+	}
+	return chartDir
+}
+
+// getLocalDependencies returns the chart directories each chart depends on through a file:// repository.
+func getLocalDependencies(chartDirs map[string]string) map[string][]string {
+	dependencies := make(map[string][]string)
+	for chartDir := range chartDirs {
+		chartFile, err := chartutil.LoadChartfile(filepath.Join(chartDir, chartutil.ChartfileName))
+		if err != nil {
+			continue
+		}
+		for _, dependency := range chartFile.Dependencies {
+			if !strings.HasPrefix(dependency.Repository, fileRepositoryPrefix) {
+				continue
+			}
+			dependencyDir := strings.TrimPrefix(dependency.Repository, fileRepositoryPrefix)
+			if !filepath.IsAbs(dependencyDir) {
+				dependencyDir = filepath.Join(chartDir, dependencyDir)
+			}
+			if dependencyDir, err = getRealPath(dependencyDir); err != nil {
+				continue
+			}
+			if _, ok := chartDirs[dependencyDir]; ok {
+				dependencies[chartDir] = append(dependencies[chartDir], dependencyDir)
+			}
+		}
+	}
+	return dependencies
+}
+
+// getRealPath returns the absolute path of a file with symbolic links resolved, for paths of the working tree and
+// paths of charts to be compared.
+func getRealPath(file string) (string, error) {
+	absPath, err := filepath.Abs(file)
+	if err != nil {
+		return "", err
+	}
+	realPath, err := filepath.EvalSymlinks(absPath)
+	if err != nil {
+		return "", err
+	}
+	return realPath, nil
+}
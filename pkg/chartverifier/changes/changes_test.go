@@ -0,0 +1,114 @@
+package changes
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir string, file string, content string) {
+	filePath := filepath.Join(dir, filepath.FromSlash(file))
+	require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+	require.NoError(t, ioutil.WriteFile(filePath, []byte(content), 0644))
+}
+
+func commitAll(t *testing.T, worktree *git.Worktree, message string) {
+	_, err := worktree.Add(".")
+	require.NoError(t, err)
+	_, err = worktree.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+}
+
+// newTestRepository creates a repository with charts base, app depending on base, top depending on app, and other.
+func newTestRepository(t *testing.T) (string, *git.Worktree) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	writeFile(t, dir, "charts/base/Chart.yaml", "apiVersion: v2\nname: base\nversion: 0.1.0\n")
+	writeFile(t, dir, "charts/base/values.yaml", "replicas: 1\n")
+	writeFile(t, dir, "charts/app/Chart.yaml", "apiVersion: v2\nname: app\nversion: 0.1.0\n"+
+		"dependencies:\n  - name: base\n    version: 0.1.0\n    repository: file://../base\n")
+	writeFile(t, dir, "charts/top/Chart.yaml", "apiVersion: v2\nname: top\nversion: 0.1.0\n"+
+		"dependencies:\n  - name: app\n    version: 0.1.0\n    repository: file://../app\n")
+	writeFile(t, dir, "charts/other/Chart.yaml", "apiVersion: v2\nname: other\nversion: 0.1.0\n")
+	writeFile(t, dir, "README.md", "charts\n")
+	commitAll(t, worktree, "Add charts")
+	return dir, worktree
+}
+
+func TestGetChangedCharts(t *testing.T) {
+
+	t.Run("No change", func(t *testing.T) {
+		dir, _ := newTestRepository(t)
+		charts, err := GetChangedCharts(dir, "HEAD")
+		require.NoError(t, err)
+		require.Empty(t, charts)
+	})
+
+	t.Run("Changed dependency", func(t *testing.T) {
+		dir, worktree := newTestRepository(t)
+		writeFile(t, dir, "charts/base/values.yaml", "replicas: 2\n")
+		writeFile(t, dir, "README.md", "charts and more\n")
+		commitAll(t, worktree, "Scale base")
+
+		charts, err := GetChangedCharts(dir, "HEAD~1")
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			filepath.Join(dir, "charts", "app"),
+			filepath.Join(dir, "charts", "base"),
+			filepath.Join(dir, "charts", "top"),
+		}, charts)
+	})
+
+	t.Run("Uncommitted change", func(t *testing.T) {
+		dir, _ := newTestRepository(t)
+		writeFile(t, dir, "charts/other/templates/configmap.yaml", "kind: ConfigMap\n")
+
+		charts, err := GetChangedCharts(dir, "HEAD")
+		require.NoError(t, err)
+		require.Equal(t, []string{filepath.Join(dir, "charts", "other")}, charts)
+	})
+
+	t.Run("Changes on the ref after the merge base are ignored", func(t *testing.T) {
+		dir, worktree := newTestRepository(t)
+		repo, err := git.PlainOpen(dir)
+		require.NoError(t, err)
+		head, err := repo.Head()
+		require.NoError(t, err)
+
+		// main moves on with a change to other, while the branch changes top.
+		writeFile(t, dir, "charts/other/values.yaml", "enabled: true\n")
+		commitAll(t, worktree, "Change other")
+		mainHead, err := repo.Head()
+		require.NoError(t, err)
+		require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Hash: head.Hash()}))
+		writeFile(t, dir, "charts/top/values.yaml", "enabled: true\n")
+		commitAll(t, worktree, "Change top")
+
+		charts, err := GetChangedCharts(dir, mainHead.Hash().String())
+		require.NoError(t, err)
+		require.Equal(t, []string{filepath.Join(dir, "charts", "top")}, charts)
+	})
+
+	t.Run("Unknown ref", func(t *testing.T) {
+		dir, _ := newTestRepository(t)
+		_, err := GetChangedCharts(dir, "does-not-exist")
+		require.Error(t, err)
+	})
+
+	t.Run("Not a repository", func(t *testing.T) {
+		_, err := GetChangedCharts(t.TempDir(), "HEAD")
+		require.Error(t, err)
+	})
+}
@@ -1,36 +1,54 @@
 package checks
 
 const (
-	HasReadme                  CheckName = "has-readme"
-	IsHelmV3                   CheckName = "is-helm-v3"
-	ContainsTest               CheckName = "contains-test"
-	ContainsValues             CheckName = "contains-values"
-	ContainsValuesSchema       CheckName = "contains-values-schema"
-	HasKubeVersion             CheckName = "has-kubeversion"
-	NotContainsCRDs            CheckName = "not-contains-crds"
-	HelmLint                   CheckName = "helm-lint"
-	NotContainCsiObjects       CheckName = "not-contain-csi-objects"
-	ImagesAreCertified         CheckName = "images-are-certified"
-	ChartTesting               CheckName = "chart-testing"
-	RequiredAnnotationsPresent CheckName = "required-annotations-present"
-	SignatureIsValid           CheckName = "signature-is-valid"
-	MandatoryCheckType         CheckType = "Mandatory"
-	OptionalCheckType          CheckType = "Optional"
-	ExperimentalCheckType      CheckType = "Experimental"
+	HasReadme                      CheckName = "has-readme"
+	IsHelmV3                       CheckName = "is-helm-v3"
+	ContainsTest                   CheckName = "contains-test"
+	ContainsValues                 CheckName = "contains-values"
+	ContainsValuesSchema           CheckName = "contains-values-schema"
+	HasKubeVersion                 CheckName = "has-kubeversion"
+	NotContainsCRDs                CheckName = "not-contains-crds"
+	HelmLint                       CheckName = "helm-lint"
+	NotContainCsiObjects           CheckName = "not-contain-csi-objects"
+	ImagesAreCertified             CheckName = "images-are-certified"
+	ChartTesting                   CheckName = "chart-testing"
+	RequiredAnnotationsPresent     CheckName = "required-annotations-present"
+	SignatureIsValid               CheckName = "signature-is-valid"
+	RunsWithRestrictedSCC          CheckName = "runs-with-restricted-scc"
+	HasLeastPrivilegeRBAC          CheckName = "has-least-privilege-rbac"
+	KeywordsAreOpenshiftCategories CheckName = "keywords-are-openshift-categories"
+	IsCommercialChart              CheckName = "is-commercial-chart"
+	IsCommunityChart               CheckName = "is-community-chart"
+	HasResourceRequirements        CheckName = "has-resource-requirements"
+	DependenciesAreValid           CheckName = "dependencies-are-valid"
+	HasCompleteMetadata            CheckName = "has-complete-metadata"
+	HasCompleteReadme              CheckName = "has-complete-readme"
+	MandatoryCheckType             CheckType = "Mandatory"
+	OptionalCheckType              CheckType = "Optional"
+	ExperimentalCheckType          CheckType = "Experimental"
 )
 
 var setCheckNames = []CheckName{ChartTesting,
 	ContainsTest,
 	ContainsValuesSchema,
 	ContainsValues,
+	DependenciesAreValid,
+	HasCompleteMetadata,
+	HasCompleteReadme,
 	HasKubeVersion,
+	HasLeastPrivilegeRBAC,
 	HasReadme,
+	HasResourceRequirements,
 	HelmLint,
 	ImagesAreCertified,
+	IsCommercialChart,
+	IsCommunityChart,
 	IsHelmV3,
+	KeywordsAreOpenshiftCategories,
 	NotContainCsiObjects,
 	NotContainsCRDs,
 	RequiredAnnotationsPresent,
+	RunsWithRestrictedSCC,
 	SignatureIsValid}
 
 func GetChecks() []CheckName {
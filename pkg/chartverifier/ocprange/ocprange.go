@@ -0,0 +1,171 @@
+// Package ocprange maps the kubeVersion constraint of a chart to the OpenShift versions the chart supports.
+package ocprange
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver"
+)
+
+// versionToken matches the versions mentioned in a constraint, the patch being captured when it is a number.
+var versionToken = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// operatorSpace matches an operator followed by the whitespace before its version.
+var operatorSpace = regexp.MustCompile(`(!=|>=|=>|<=|=<|~>|[=><~^])\s+`)
+
+// Range is a range of OpenShift minor versions, from From to To included.
+type Range struct {
+	From string
+	To   string
+	// OpenEnded is set when the range includes every OpenShift version after From, To being empty.
+	OpenEnded bool
+}
+
+// String formats the range as an OpenShift version, such as 4.9, as a closed range, such as 4.7 - 4.9, or as an open
+// range, such as >=4.9.
+func (r Range) String() string {
+	if r.OpenEnded {
+		return fmt.Sprintf(">=%s", r.From)
+	}
+	if r.From == r.To {
+		return r.From
+	}
+	return fmt.Sprintf("%s - %s", r.From, r.To)
+}
+
+// Ranges are disjoint ranges of OpenShift versions, in ascending order.
+type Ranges []Range
+
+// String formats the ranges as alternatives, such as 4.7 - 4.8 || >=4.11.
+func (r Ranges) String() string {
+	ranges := make([]string, 0, len(r))
+	for _, ocpRange := range r {
+		ranges = append(ranges, ocpRange.String())
+	}
+	return strings.Join(ranges, " || ")
+}
+
+type versionMapping struct {
+	kubeVersion *semver.Version
+	ocpVersion  string
+}
+
+// GetRanges returns the minimal set of OpenShift version ranges matching a kubeVersion constraint, using a map of
+// Kubernetes major.minor versions to the OpenShift major.minor version they first ship in.
+//
+// A Kubernetes minor version is supported if the constraint matches any of its releases, pre-release versions being
+// ignored. The last range is open-ended when the constraint also matches the Kubernetes versions after the map.
+func GetRanges(kubeVersionConstraint string, kubeOpenShiftVersionMap map[string]string) (Ranges, error) {
+
+	constraint, err := semver.NewConstraint(toCommaConstraint(kubeVersionConstraint))
+	if err != nil {
+		return nil, fmt.Errorf("improper constraint: %s", kubeVersionConstraint)
+	}
+
+	mappings := make([]versionMapping, 0, len(kubeOpenShiftVersionMap))
+	for kubeVersion, ocpVersion := range kubeOpenShiftVersionMap {
+		version, err := semver.NewVersion(kubeVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Kubernetes version %q in version map: %v", kubeVersion, err)
+		}
+		mappings = append(mappings, versionMapping{kubeVersion: version, ocpVersion: ocpVersion})
+	}
+	if len(mappings) == 0 {
+		return nil, fmt.Errorf("the version map is empty")
+	}
+	sort.Slice(mappings, func(i, j int) bool {
+		return mappings[i].kubeVersion.LessThan(mappings[j].kubeVersion)
+	})
+
+	patches := getMentionedPatches(kubeVersionConstraint)
+
+	var ranges Ranges
+	inRange := false
+	for _, mapping := range mappings {
+		if !matchesMinor(constraint, patches, mapping.kubeVersion.Major(), mapping.kubeVersion.Minor()) {
+			inRange = false
+			continue
+		}
+		if inRange {
+			ranges[len(ranges)-1].To = mapping.ocpVersion
+		} else {
+			ranges = append(ranges, Range{From: mapping.ocpVersion, To: mapping.ocpVersion})
+			inRange = true
+		}
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("Failed to determine a minimum OCP version")
+	}
+
+	// the minor version after the latest mapped and mentioned ones stands for every later Kubernetes version.
+	latest := mappings[len(mappings)-1].kubeVersion
+	nextMinor := latest.Minor() + 1
+	for minor := range patches[latest.Major()] {
+		if minor >= nextMinor {
+			nextMinor = minor + 1
+		}
+	}
+	if inRange && matchesMinor(constraint, patches, latest.Major(), nextMinor) {
+		ranges[len(ranges)-1].To = ""
+		ranges[len(ranges)-1].OpenEnded = true
+	}
+	return ranges, nil
+}
+
+// toCommaConstraint rewrites a constraint in which the constraints of an AND are separated by whitespace, as helm accepts
+// for kubeVersion, to a constraint in which they are separated by commas. Hyphen ranges, such as 1.20 - 1.22, are kept.
+func toCommaConstraint(kubeVersionConstraint string) string {
+	ors := strings.Split(kubeVersionConstraint, "||")
+	for i, or := range ors {
+		tokens := strings.Fields(strings.ReplaceAll(operatorSpace.ReplaceAllString(or, "$1"), ",", " "))
+		ands := make([]string, 0, len(tokens))
+		for j := 0; j < len(tokens); j++ {
+			if tokens[j] == "-" && len(ands) > 0 && j+1 < len(tokens) {
+				ands[len(ands)-1] += " - " + tokens[j+1]
+				j++
+				continue
+			}
+			ands = append(ands, tokens[j])
+		}
+		ors[i] = strings.Join(ands, ", ")
+	}
+	return strings.Join(ors, " || ")
+}
+
+// getMentionedPatches returns the patch versions mentioned in a constraint, by major then minor version.
+func getMentionedPatches(kubeVersionConstraint string) map[int64]map[int64][]int64 {
+	patches := make(map[int64]map[int64][]int64)
+	for _, match := range versionToken.FindAllStringSubmatch(kubeVersionConstraint, -1) {
+		major, _ := strconv.ParseInt(match[1], 10, 64)
+		minor, _ := strconv.ParseInt(match[2], 10, 64)
+		if patches[major] == nil {
+			patches[major] = make(map[int64][]int64)
+		}
+		if len(match[3]) > 0 {
+			patch, _ := strconv.ParseInt(match[3], 10, 64)
+			patches[major][minor] = append(patches[major][minor], patch)
+		} else if _, ok := patches[major][minor]; !ok {
+			patches[major][minor] = nil
+		}
+	}
+	return patches
+}
+
+// matchesMinor checks if the constraint matches a release of a minor version. A constraint can only change outcome at
+// the versions it mentions, so the first release and the releases at and after each mentioned patch are enough.
+func matchesMinor(constraint *semver.Constraints, patches map[int64]map[int64][]int64, major, minor int64) bool {
+	candidates := []int64{0}
+	for _, patch := range patches[major][minor] {
+		candidates = append(candidates, patch, patch+1)
+	}
+	for _, patch := range candidates {
+		if constraint.Check(semver.MustParse(fmt.Sprintf("%d.%d.%d", major, minor, patch))) {
+			return true
+		}
+	}
+	return false
+}
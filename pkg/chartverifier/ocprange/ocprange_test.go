@@ -0,0 +1,79 @@
+package ocprange
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testVersionMap = map[string]string{
+	"1.19": "4.6",
+	"1.20": "4.7",
+	"1.21": "4.8",
+	"1.22": "4.9",
+	"1.23": "4.10",
+	"1.24": "4.11",
+	"1.25": "4.12",
+}
+
+func TestGetRanges(t *testing.T) {
+
+	type testCase struct {
+		description string
+		kubeVersion string
+		ocpRange    string
+	}
+
+	testCases := []testCase{
+		{description: "Any version", kubeVersion: "*", ocpRange: ">=4.6"},
+		{description: "Single minor version", kubeVersion: "~1.22", ocpRange: "4.9"},
+		{description: "Hyphen range", kubeVersion: "1.20 - 1.23", ocpRange: "4.7 - 4.10"},
+		{description: "Space separated bounds", kubeVersion: ">= 1.20.0 < 1.22.0", ocpRange: "4.7 - 4.8"},
+		{description: "Comma separated bounds", kubeVersion: ">=1.20.0, <1.22.0", ocpRange: "4.7 - 4.8"},
+		{description: "Open-ended range", kubeVersion: ">=1.21", ocpRange: ">=4.8"},
+		{description: "Range ending with the map", kubeVersion: ">=1.21 <1.26", ocpRange: "4.8 - 4.12"},
+		{description: "Range ending after the map", kubeVersion: ">=1.21 <1.30", ocpRange: "4.8 - 4.12"},
+		{description: "Gap between ranges", kubeVersion: ">=1.20 <1.22 || >=1.24", ocpRange: "4.7 - 4.8 || >=4.11"},
+		{description: "Excluded minor version", kubeVersion: ">=1.19 <1.22 || >=1.23 <1.25", ocpRange: "4.6 - 4.8 || 4.10 - 4.11"},
+		{description: "Excluded patch version", kubeVersion: "!=1.22.0", ocpRange: ">=4.6"},
+		{description: "Minor version from a patch version", kubeVersion: ">1.21.5 <1.23", ocpRange: "4.8 - 4.9"},
+		{description: "Bound below the last patch version", kubeVersion: "<=1.21.0", ocpRange: "4.6 - 4.8"},
+		{description: "Pre-release lower bound", kubeVersion: ">=1.22.0-0", ocpRange: ">=4.9"},
+		{description: "Pre-release upper bound", kubeVersion: "<1.22.0-0", ocpRange: "4.6 - 4.8"},
+		{description: "Pre-release version only", kubeVersion: "1.22.0-rc.1 || 1.24.x", ocpRange: "4.11"},
+		{description: "Caret range", kubeVersion: "^1.23", ocpRange: ">=4.10"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			ranges, err := GetRanges(test.kubeVersion, testVersionMap)
+			require.NoError(t, err)
+			require.Equal(t, test.ocpRange, ranges.String())
+		})
+	}
+}
+
+func TestGetRangesStructure(t *testing.T) {
+
+	ranges, err := GetRanges(">=1.20 <1.22 || >=1.24", testVersionMap)
+	require.NoError(t, err)
+	require.Equal(t, Ranges{{From: "4.7", To: "4.8"}, {From: "4.11", OpenEnded: true}}, ranges)
+}
+
+func TestGetRangesErrors(t *testing.T) {
+
+	_, err := GetRanges(">=1.30", testVersionMap)
+	require.EqualError(t, err, "Failed to determine a minimum OCP version")
+
+	_, err = GetRanges("<1.19", testVersionMap)
+	require.EqualError(t, err, "Failed to determine a minimum OCP version")
+
+	_, err = GetRanges("not a version", testVersionMap)
+	require.EqualError(t, err, "improper constraint: not a version")
+
+	_, err = GetRanges("*", map[string]string{})
+	require.EqualError(t, err, "the version map is empty")
+
+	_, err = GetRanges("*", map[string]string{"one": "4.1"})
+	require.Error(t, err)
+}
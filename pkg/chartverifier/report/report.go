@@ -18,6 +18,8 @@ const (
 	PassOutcomeType    OutcomeType = "PASS"
 	SkippedOutcomeType OutcomeType = "SKIPPED"
 	UnknownOutcomeType OutcomeType = "UNKNOWN"
+	// AcceptedOutcomeType is the outcome of a failure accepted by a baseline.
+	AcceptedOutcomeType OutcomeType = "ACCEPTED"
 
 	JsonReport ReportFormat = "json"
 	YamlReport ReportFormat = "yaml"
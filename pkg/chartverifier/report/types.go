@@ -38,6 +38,49 @@ type ToolMetadata struct {
 	SupportedOpenShiftVersions string  `json:"supportedOpenShiftVersions,omitempty" yaml:"supportedOpenShiftVersions,omitempty"`
 	ProviderDelivery           bool    `json:"providerControlledDelivery,omitempty" yaml:"providerControlledDelivery,omitempty"`
 	WebCatalogOnly             bool    `json:"webCatalogOnly" yaml:"webCatalogOnly" hash:"ignore"`
+	// TestedCluster describes the cluster the chart-testing check ran on.
+	TestedCluster *ClusterMetadata `json:"testedCluster,omitempty" yaml:"testedCluster,omitempty" hash:"ignore"`
+	// VersionMap is the version of the Kubernetes to OpenShift version map used.
+	VersionMap string `json:"versionMap,omitempty" yaml:"versionMap,omitempty" hash:"ignore"`
+	// Baseline identifies the baseline accepting known failures of the checks, when one is used.
+	Baseline *BaselineMetadata `json:"baseline,omitempty" yaml:"baseline,omitempty"`
+}
+
+// HashInclude leaves the baseline out of the report digest when none is used, so the digest of a report is the same
+// as before it was added.
+func (t ToolMetadata) HashInclude(field string, v interface{}) (bool, error) {
+	return field != "Baseline" || t.Baseline != nil, nil
+}
+
+// BaselineMetadata identifies a baseline and the entries of it which accepted a failure.
+type BaselineMetadata struct {
+	Uri    string `json:"uri" yaml:"uri"`
+	Digest string `json:"digest" yaml:"digest"`
+	// Applied are the entries which accepted a failure of a check of the report.
+	Applied []*BaselineEntry `json:"applied,omitempty" yaml:"applied,omitempty"`
+}
+
+// BaselineEntry accepts the failures of a check, all of them or only those matching a reason pattern or a
+// fingerprint, until an expiry date.
+type BaselineEntry struct {
+	// Check is the name of the check, with or without its version: v1.0/images-are-certified or images-are-certified.
+	Check string `json:"check" yaml:"check"`
+	// Reason is a regular expression the reason of the failure must match.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	// Fingerprint is the fingerprint of the failure in the report.
+	Fingerprint string `json:"fingerprint,omitempty" yaml:"fingerprint,omitempty"`
+	// Expires is the last day the failure is accepted, as yyyy-mm-dd.
+	Expires string `json:"expires,omitempty" yaml:"expires,omitempty"`
+	Comment string `json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+type ClusterMetadata struct {
+	Distribution string `json:"distribution" yaml:"distribution"`
+	Platform     string `json:"platform,omitempty" yaml:"platform,omitempty"`
+	Version      string `json:"version" yaml:"version"`
+	// Emulated indicates the cluster is an ephemeral cluster running the Kubernetes version of the tested OpenShift
+	// version, rather than OpenShift.
+	Emulated bool `json:"emulated,omitempty" yaml:"emulated,omitempty"`
 }
 
 type Digests struct {
@@ -56,6 +99,37 @@ type CheckReport struct {
 	Type    apichecks.CheckType `json:"type" yaml:"type"`
 	Outcome OutcomeType         `json:"outcome" yaml:"outcome"`
 	Reason  string              `json:"reason" yaml:"reason"`
+	// Fingerprint identifies a failure, for a baseline to accept it.
+	Fingerprint string `json:"fingerprint,omitempty" yaml:"fingerprint,omitempty" hash:"ignore"`
+	// Subcharts contains the result of each check run on each subchart, for a check which runs the checks of the
+	// profile on subcharts.
+	Subcharts []*SubchartCheckReport `json:"subcharts,omitempty" yaml:"subcharts,omitempty"`
+	// ClusterUnavailable indicates the check failed because its cluster could not be reached, rather than because
+	// of the chart.
+	ClusterUnavailable bool `json:"clusterUnavailable,omitempty" yaml:"clusterUnavailable,omitempty"`
+}
+
+// HashInclude leaves out of the report digest the subchart results of a check which has none, and the cluster
+// availability of a check which could reach its cluster, so the digest of a report is the same as before they were
+// added.
+func (c CheckReport) HashInclude(field string, v interface{}) (bool, error) {
+	switch field {
+	case "Subcharts":
+		return len(c.Subcharts) > 0, nil
+	case "ClusterUnavailable":
+		return c.ClusterUnavailable, nil
+	}
+	return true, nil
+}
+
+// SubchartCheckReport is the result of a check of the profile run on a subchart.
+type SubchartCheckReport struct {
+	// Subchart is the path of the subchart in the chart, for example charts/postgresql.
+	Subchart string              `json:"subchart" yaml:"subchart"`
+	Check    apichecks.CheckName `json:"check" yaml:"check"`
+	Type     apichecks.CheckType `json:"type" yaml:"type"`
+	Outcome  OutcomeType         `json:"outcome" yaml:"outcome"`
+	Reason   string              `json:"reason" yaml:"reason"`
 }
 
 type reportOptions struct {
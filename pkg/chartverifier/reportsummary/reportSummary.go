@@ -223,62 +223,77 @@ func (r *ReportSummary) addMetadata() {
 
 func (r *ReportSummary) addResults() {
 
-	profileVendorType := r.options.report.Metadata.ToolMetadata.Profile.VendorType
-	profileVersion := r.options.report.Metadata.ToolMetadata.Profile.Version
+	outcome := GetResultsOutcome(r.options.report, r.options.values)
 
-	if configVendorType, ok := r.options.values[profiles.VendorTypeConfigName]; ok {
+	r.ResultsReport = &ResultsReport{}
+
+	r.ResultsReport.Passed = fmt.Sprintf("%d", outcome.Passed)
+	r.ResultsReport.Failed = fmt.Sprintf("%d", len(outcome.MandatoryFailed))
+	if len(outcome.Accepted) > 0 {
+		r.ResultsReport.Accepted = fmt.Sprintf("%d", len(outcome.Accepted))
+	}
+	r.ResultsReport.Messages = outcome.Messages
+
+}
+
+// GetResultsOutcome evaluates the results of a report against the checks of its profile, or of the profile set in the
+// values. A mandatory check missing from the report fails, and a failure accepted by a baseline neither passes nor
+// fails.
+func GetResultsOutcome(verifyReport *report.Report, values map[string]interface{}) *ResultsOutcome {
+
+	profileVendorType := verifyReport.Metadata.ToolMetadata.Profile.VendorType
+	profileVersion := verifyReport.Metadata.ToolMetadata.Profile.Version
+
+	if configVendorType, ok := values[profiles.VendorTypeConfigName]; ok {
 		useVendorType := profiles.VendorType(fmt.Sprintf("%v", configVendorType))
 		if len(useVendorType) > 0 {
 			profileVendorType = string(useVendorType)
 		}
 	}
-	if configProfileVersion, ok := r.options.values[profiles.VersionConfigName]; ok {
+	if configProfileVersion, ok := values[profiles.VersionConfigName]; ok {
 		useProfileVersion := fmt.Sprintf("%v", configProfileVersion)
 		if len(useProfileVersion) > 0 {
 			profileVersion = useProfileVersion
 		}
 	}
 
-	values := make(map[string]interface{})
-	values[profiles.VendorTypeConfigName] = profileVendorType
-	values[profiles.VersionConfigName] = profileVersion
+	profileValues := make(map[string]interface{})
+	profileValues[profiles.VendorTypeConfigName] = profileVendorType
+	profileValues[profiles.VersionConfigName] = profileVersion
 
-	profile := profiles.New(values)
+	profile := profiles.New(profileValues)
 
-	passed := 0
-	failed := 0
-	var messages []string
+	outcome := &ResultsOutcome{}
 
 	for _, profileCheck := range profile.Checks {
-		if profileCheck.Type == checks.MandatoryCheckType {
-			found := false
-			for _, reportCheck := range r.options.report.Results {
-				if strings.Compare(profileCheck.Name, string(reportCheck.Check)) == 0 {
-					found = true
-					if reportCheck.Outcome == report.PassOutcomeType || reportCheck.Outcome == report.SkippedOutcomeType {
-						passed++
-					} else {
-						failed++
-						// Change multiple line reasons to a single line
-						reason := strings.ReplaceAll(strings.TrimRight(reportCheck.Reason, "\n"), "\n", ", ")
-						messages = append(messages, reason)
+		found := false
+		for _, reportCheck := range verifyReport.Results {
+			if strings.Compare(profileCheck.Name, string(reportCheck.Check)) == 0 {
+				found = true
+				if reportCheck.Outcome == report.AcceptedOutcomeType {
+					outcome.Accepted = append(outcome.Accepted, reportCheck.Check)
+				} else if profileCheck.Type != checks.MandatoryCheckType {
+					if reportCheck.Outcome == report.FailOutcomeType {
+						outcome.OptionalFailed = append(outcome.OptionalFailed, reportCheck.Check)
 					}
-					break
+				} else if reportCheck.Outcome == report.PassOutcomeType || reportCheck.Outcome == report.SkippedOutcomeType {
+					outcome.Passed++
+				} else {
+					outcome.MandatoryFailed = append(outcome.MandatoryFailed, reportCheck.Check)
+					// Change multiple line reasons to a single line
+					reason := strings.ReplaceAll(strings.TrimRight(reportCheck.Reason, "\n"), "\n", ", ")
+					outcome.Messages = append(outcome.Messages, reason)
 				}
+				break
 			}
-			if !found {
-				failed++
-				messages = append(messages, fmt.Sprintf("Missing mandatory check : %s", profileCheck.Name))
-			}
+		}
+		if !found && profileCheck.Type == checks.MandatoryCheckType {
+			outcome.MandatoryFailed = append(outcome.MandatoryFailed, checks.CheckName(profileCheck.Name))
+			outcome.Messages = append(outcome.Messages, fmt.Sprintf("Missing mandatory check : %s", profileCheck.Name))
 		}
 	}
 
-	r.ResultsReport = &ResultsReport{}
-
-	r.ResultsReport.Passed = fmt.Sprintf("%d", passed)
-	r.ResultsReport.Failed = fmt.Sprintf("%d", failed)
-	r.ResultsReport.Messages = messages
-
+	return outcome
 }
 
 func (r *ReportSummary) checkReportDigest() error {
@@ -10,6 +10,8 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/redhat-certification/chart-verifier/internal/chartverifier/profiles"
+	"github.com/redhat-certification/chart-verifier/pkg/chartverifier/checks"
 	apireport "github.com/redhat-certification/chart-verifier/pkg/chartverifier/report"
 )
 
@@ -167,6 +169,41 @@ func TestAddUrlReport(t *testing.T) {
 
 }
 
+func TestGetResultsOutcome(t *testing.T) {
+
+	reportBytes, err := loadChartFromAbsPath("test-reports/report.yaml")
+	require.NoError(t, err)
+	report, err := apireport.NewReport().SetContent(string(reportBytes)).Load()
+	require.NoError(t, err)
+
+	outcome := GetResultsOutcome(report, nil)
+	require.Equal(t, 13, outcome.Passed)
+	require.Empty(t, outcome.MandatoryFailed)
+	require.Empty(t, outcome.OptionalFailed)
+	require.Empty(t, outcome.Messages)
+
+	report.Results[0].Outcome = apireport.FailOutcomeType
+	report.Results[0].Reason = "Values file does not exist\n"
+	report.Results = append(report.Results[:1], report.Results[2:]...)
+	// has-complete-readme is an optional check of the v1.3 profiles.
+	report.Metadata.ToolMetadata.Profile.Version = "v1.3"
+	report.Results = append(report.Results, &apireport.CheckReport{Check: "v1.0/has-complete-readme", Outcome: apireport.FailOutcomeType})
+	report.Results[1].Outcome = apireport.AcceptedOutcomeType
+
+	outcome = GetResultsOutcome(report, nil)
+	require.Equal(t, 10, outcome.Passed)
+	require.Equal(t, []checks.CheckName{"v1.0/has-readme", "v1.0/contains-values"}, outcome.MandatoryFailed)
+	require.Equal(t, []checks.CheckName{"v1.0/has-complete-readme"}, outcome.OptionalFailed)
+	require.Equal(t, []checks.CheckName{"v1.1/images-are-certified"}, outcome.Accepted)
+	require.Equal(t, []string{"Missing mandatory check : v1.0/has-readme", "Values file does not exist"}, outcome.Messages)
+
+	// helm-lint is the only mandatory check of the community profile.
+	outcome = GetResultsOutcome(report, map[string]interface{}{profiles.VendorTypeConfigName: "community", profiles.VersionConfigName: "v1.3"})
+	require.Equal(t, 1, outcome.Passed)
+	require.Empty(t, outcome.MandatoryFailed)
+	require.Equal(t, []checks.CheckName{"v1.0/contains-values", "v1.0/has-complete-readme"}, outcome.OptionalFailed)
+}
+
 func checkReportSummaries(summary APIReportSummary, chartUri string, t *testing.T) {
 	checkReportSummariesFormat(YamlReport, summary, chartUri, t)
 	checkReportSummariesFormat(JsonReport, summary, chartUri, t)
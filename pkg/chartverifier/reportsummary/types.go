@@ -2,6 +2,7 @@ package reportsummary
 
 import (
 	"github.com/redhat-certification/chart-verifier/internal/chartverifier/profiles"
+	apichecks "github.com/redhat-certification/chart-verifier/pkg/chartverifier/checks"
 	apireport "github.com/redhat-certification/chart-verifier/pkg/chartverifier/report"
 	helmchart "helm.sh/helm/v3/pkg/chart"
 )
@@ -40,9 +41,24 @@ type MetadataReport struct {
 type ResultsReport struct {
 	Passed   string   `json:"passed" yaml:"passed"`
 	Failed   string   `json:"failed" yaml:"failed"`
+	Accepted string   `json:"accepted,omitempty" yaml:"accepted,omitempty"`
 	Messages []string `json:"message" yaml:"message"`
 }
 
+// ResultsOutcome is the outcome of the results of a report evaluated against the checks of a profile.
+type ResultsOutcome struct {
+	// Passed is the number of mandatory checks which passed or were skipped.
+	Passed int
+	// MandatoryFailed are the mandatory checks which failed or are missing from the report.
+	MandatoryFailed []apichecks.CheckName
+	// OptionalFailed are the checks of the profile which are not mandatory and failed.
+	OptionalFailed []apichecks.CheckName
+	// Accepted are the checks of the profile, mandatory or not, whose failure is accepted by a baseline.
+	Accepted []apichecks.CheckName
+	// Messages are the reasons the mandatory checks failed, each on a single line.
+	Messages []string
+}
+
 type reportOptions struct {
 	report       *apireport.Report
 	values       map[string]interface{}
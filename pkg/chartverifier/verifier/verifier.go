@@ -60,6 +60,8 @@ const (
 	ChartValues      StringKey = "chart-values"
 	KubeAsGroups     StringKey = "kube-as-group"
 	PGPPublicKey     StringKey = "pgp-public-key"
+	VersionMap       StringKey = "version-map"
+	Baseline         StringKey = "baseline"
 
 	ChartSet       ValuesKey = "chart-set"
 	ChartSetFile   ValuesKey = "chart-set-file"
@@ -69,6 +71,8 @@ const (
 	WebCatalogOnly   BooleanKey = "web-catalog-only"
 	ProviderDelivery BooleanKey = "provider-delivery" // Deprecated in 1.10
 	SuppressErrorLog BooleanKey = "suppress-error-log"
+	KeepOnFailure    BooleanKey = "keep-on-failure"
+	EphemeralCluster BooleanKey = "ephemeral-cluster"
 
 	Timeout            DurationKey = "timeout"
 	HelmInstallTimeout DurationKey = "helm-install-timeout"
@@ -88,14 +92,16 @@ var setStringKeys = [...]StringKey{KubeApiServer,
 	Config,
 	ChartValues,
 	KubeAsGroups,
-	PGPPublicKey}
+	PGPPublicKey,
+	VersionMap,
+	Baseline}
 
 var setValuesKeys = [...]ValuesKey{CommandSet,
 	ChartSet,
 	ChartSetFile,
 	ChartSetString}
 
-var setBooleanKeys = [...]BooleanKey{WebCatalogOnly, SuppressErrorLog}
+var setBooleanKeys = [...]BooleanKey{WebCatalogOnly, SuppressErrorLog, KeepOnFailure, EphemeralCluster}
 
 var setDurationKeys = [...]DurationKey{Timeout, HelmInstallTimeout}
 
@@ -349,6 +355,14 @@ func (v *Verifier) Run(chart_uri string) (ApiVerifier, error) {
 		runOptions.SuppressErrorLog = booleanValue
 	}
 
+	if booleanValue, ok := v.Inputs.Flags.BooleanFlags[KeepOnFailure]; ok {
+		runOptions.KeepOnFailure = booleanValue
+	}
+
+	if booleanValue, ok := v.Inputs.Flags.BooleanFlags[EphemeralCluster]; ok {
+		runOptions.EphemeralCluster = booleanValue
+	}
+
 	if durationValue, ok := v.Inputs.Flags.DurationFlags[Timeout]; ok {
 		runOptions.ClientTimeout = durationValue
 	}
@@ -357,6 +371,14 @@ func (v *Verifier) Run(chart_uri string) (ApiVerifier, error) {
 		runOptions.PublicKeys = stringsValue
 	}
 
+	if stringsValue, ok := v.Inputs.Flags.StringFlags[VersionMap]; ok && len(stringsValue) > 0 {
+		runOptions.VersionMap = stringsValue[0]
+	}
+
+	if stringsValue, ok := v.Inputs.Flags.StringFlags[Baseline]; ok && len(stringsValue) > 0 {
+		runOptions.Baseline = stringsValue[0]
+	}
+
 	if durationValue, ok := v.Inputs.Flags.DurationFlags[HelmInstallTimeout]; ok {
 		runOptions.HelmInstallTimeout = durationValue
 	}
@@ -397,6 +419,8 @@ func (v *Verifier) initialize() {
 	v.Inputs.Flags.BooleanFlags = make(map[BooleanKey]bool)
 	v.Inputs.Flags.BooleanFlags[WebCatalogOnly] = false
 	v.Inputs.Flags.BooleanFlags[SuppressErrorLog] = false
+	v.Inputs.Flags.BooleanFlags[KeepOnFailure] = false
+	v.Inputs.Flags.BooleanFlags[EphemeralCluster] = false
 	v.Inputs.Flags.DurationFlags = make(map[DurationKey]time.Duration)
 	v.Inputs.Flags.Checks = make(map[checks.CheckName]CheckStatus)
 